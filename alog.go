@@ -15,6 +15,7 @@ import (
 	"runtime/debug"
 	"time"
 
+	"github.com/mylockerteam/alog/entry"
 	"github.com/mylockerteam/alog/strategy/file"
 	"github.com/mylockerteam/alog/strategy/standart"
 )
@@ -25,25 +26,37 @@ const (
 	messageFormatWithFileLine = "%s;%s:%d;%s\n"
 )
 
-// Config contains settings and registered loggers
+// internalLog is used for alog's own diagnostics. It never goes through the
+// standard logger, so they can't loop back when RedirectStdLog is active.
+var internalLog = log.New(os.Stderr, "", log.LstdFlags)
+
+// Config contains settings and registered loggers.
+// With Sync the loggers don't start readers and messages are written in the calling goroutine.
+// Messages of the StackTrace logger types get the stack trace, Sampling drops repeated entries.
+// Messages of logger types more verbose than MinLevel are dropped silently, e.g. Debug for Info
 type Config struct {
 	Loggers        Map
 	TimeFormat     string
 	IgnoreFileLine bool
+	Sync           bool
+	StackTrace     []uint
+	Sampling       *Sampling
+	MinLevel       *uint
 }
 
 // Log logger himself
 type Log struct {
-	_      Writer
-	config *Config
+	_       Writer
+	config  *Config
+	sampler *sampler
 }
 
-// Create creates an instance of the logger
+// Create creates an instance of the logger. The config is used as is, New validates it
 func Create(config *Config) Writer {
 	for _, l := range config.Loggers {
-		go l.Reader()
+		l.start(config.Sync)
 	}
-	return &Log{config: config}
+	return &Log{config: config, sampler: newSampler(config.Sampling)}
 }
 
 // Default created standart logger. Writes to stdout and stderr
@@ -53,7 +66,7 @@ func Default(chanBuffer uint) Writer {
 		Loggers:    getDefaultLoggerMap(chanBuffer),
 	}
 	for _, l := range config.Loggers {
-		go l.Reader()
+		l.start(false)
 	}
 	return &Log{config: config}
 }
@@ -83,10 +96,10 @@ func getDefaultLoggerMap(chanBuffer uint) Map {
 
 func printNotConfiguredMessage(code uint, skip int) {
 	if _, fileName, fileLine, ok := runtime.Caller(skip); ok {
-		log.Println(fmt.Sprintf("%s:%d Logger %s not configured", fileName, fileLine, Name(code)))
+		internalLog.Println(fmt.Sprintf("%s:%d Logger %s not configured", fileName, fileLine, Name(code)))
 		return
 	}
-	log.Println(fmt.Sprintf("Logger %s not configured", Name(code)))
+	internalLog.Println(fmt.Sprintf("Logger %s not configured", Name(code)))
 }
 
 // GetLoggerInterfaceByType returns io.Writer interface for logging in third-party libraries
@@ -98,65 +111,138 @@ func (a *Log) GetLoggerInterfaceByType(loggerType uint) io.Writer {
 	return &standart.Strategy{}
 }
 
+// Debug method for recording debug messages
+func (a *Log) Debug(msg string) Writer {
+	return a.write(Debug, msg, false)
+}
+
+// Debugf method of recording formatted debug messages
+func (a *Log) Debugf(format string, p ...interface{}) Writer {
+	return a.write(Debug, fmt.Sprintf(format, p...), false)
+}
+
 // Info method for recording informational messages
-func (a *Log) Info(msg string) *Log {
-	if l := a.config.Loggers[Info]; l != nil {
-		prepareLog := a.prepareLog(time.Now(), msg, 2)
-		l.Channel <- fmt.Sprintf("[%s] %s", Name(Info), prepareLog)
-	} else {
-		printNotConfiguredMessage(Info, 2)
-	}
-	return a
+func (a *Log) Info(msg string) Writer {
+	return a.write(Info, msg, false)
 }
 
 // Infof method of recording formatted informational messages
-func (a *Log) Infof(format string, p ...interface{}) *Log {
-	if l := a.config.Loggers[Info]; l != nil {
-		prepareLog := a.prepareLog(time.Now(), fmt.Sprintf(format, p...), 2)
-		l.Channel <- fmt.Sprintf("[%s] %s", Name(Info), prepareLog)
-	} else {
-		printNotConfiguredMessage(Info, 2)
-	}
-	return a
+func (a *Log) Infof(format string, p ...interface{}) Writer {
+	return a.write(Info, fmt.Sprintf(format, p...), false)
 }
 
 // Warning method for recording warning messages
-func (a *Log) Warning(msg string) *Log {
-	if a.config.Loggers[Wrn] != nil {
-		prepareLog := a.prepareLog(time.Now(), msg, 2)
-		a.config.Loggers[Wrn].Channel <- fmt.Sprintf("[%s] %s", Name(Wrn), prepareLog)
-	} else {
-		printNotConfiguredMessage(Wrn, 2)
-	}
-	return a
+func (a *Log) Warning(msg string) Writer {
+	return a.write(Wrn, msg, false)
 }
 
 // Method for recording errors without stack
-func (a *Log) Error(err error) *Log {
-	if a.config.Loggers[Err] != nil {
-		if err != nil {
-			prepareLog := a.prepareLog(time.Now(), err.Error(), 2)
-			a.config.Loggers[Err].Channel <- fmt.Sprintf("[%s] %s", Name(Err), prepareLog)
-		}
-	} else {
-		printNotConfiguredMessage(Err, 2)
+func (a *Log) Error(err error) Writer {
+	if err == nil {
+		return a.checkConfigured(Err)
 	}
-	return a
+	return a.write(Err, err.Error(), false)
 }
 
 // ErrorDebug method for recording errors with stack
-func (a *Log) ErrorDebug(err error) *Log {
-	if a.config.Loggers[Err] != nil {
-		if err != nil {
-			msg := fmt.Sprintf(messageFormatErrorDebug, a.prepareLog(time.Now(), err.Error(), 2), string(debug.Stack()))
-			a.config.Loggers[Err].Channel <- fmt.Sprintf("[%s] %s", Name(Err), msg)
+func (a *Log) ErrorDebug(err error) Writer {
+	if err == nil {
+		return a.checkConfigured(Err)
+	}
+	return a.write(Err, err.Error(), true)
+}
+
+// Enabled reports whether the logger of the given type is configured and not below the minimum level
+func (a *Log) Enabled(loggerType uint) bool {
+	return !a.belowLevel(loggerType) && a.config.Loggers[loggerType] != nil
+}
+
+// Dispatch method for recording prepared entries, e.g. from adapters of other logging libraries.
+// The caller of the entry is taken as is, the time and the stack trace are set if they are missing
+func (a *Log) Dispatch(e *entry.Entry) Writer {
+	return a.dispatch(e, false)
+}
+
+// dispatch records the entry, it gets the stack trace after sampling if it is forced or configured
+func (a *Log) dispatch(e *entry.Entry, stack bool) Writer {
+	if a.belowLevel(e.Level) {
+		return a
+	}
+	l := a.config.Loggers[e.Level]
+	if l == nil {
+		printNotConfiguredMessage(e.Level, 3)
+		return a
+	}
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+	if !a.sampler.allow(e) {
+		return a
+	}
+	if e.Stack == "" && (stack || a.stackTrace(e.Level)) {
+		e.Stack = string(debug.Stack())
+	}
+	e.LevelName = Name(e.Level)
+	l.send(record{entry: e, line: fmt.Sprintf("[%s] %s", e.LevelName, a.formatEntry(e))})
+	return a
+}
+
+func (a *Log) write(loggerType uint, msg string, stack bool) Writer {
+	if a.belowLevel(loggerType) {
+		return a
+	}
+	if a.config.Loggers[loggerType] == nil {
+		printNotConfiguredMessage(loggerType, 3)
+		return a
+	}
+	return a.dispatch(&entry.Entry{
+		Level:   loggerType,
+		Time:    time.Now(),
+		Caller:  caller(3),
+		Message: msg,
+	}, stack)
+}
+
+// belowLevel reports whether the logger type is more verbose than the minimum level,
+// custom logger types are never below it
+func (a *Log) belowLevel(loggerType uint) bool {
+	if a.config.MinLevel == nil {
+		return false
+	}
+	rank, ok := levelRank(loggerType)
+	min, _ := levelRank(*a.config.MinLevel)
+	return ok && rank < min
+}
+
+// stackTrace reports whether messages of the logger type get the stack trace
+func (a *Log) stackTrace(loggerType uint) bool {
+	for _, t := range a.config.StackTrace {
+		if t == loggerType {
+			return true
 		}
-	} else {
-		printNotConfiguredMessage(Err, 2)
+	}
+	return false
+}
+
+func (a *Log) checkConfigured(loggerType uint) Writer {
+	if a.config.Loggers[loggerType] == nil {
+		printNotConfiguredMessage(loggerType, 3)
 	}
 	return a
 }
 
+func caller(skip int) entry.Caller {
+	pc, fileName, fileLine, ok := runtime.Caller(skip)
+	if !ok {
+		return entry.Caller{}
+	}
+	c := entry.Caller{File: fileName, Line: fileLine}
+	if fn := runtime.FuncForPC(pc); fn != nil {
+		c.Function = fn.Name()
+	}
+	return c
+}
+
 func (a *Log) getTimeFormat() string {
 	if format := a.config.TimeFormat; format != "" {
 		return format
@@ -165,7 +251,24 @@ func (a *Log) getTimeFormat() string {
 }
 
 func (a *Log) prepareLog(time time.Time, msg string, skip int) string {
-	if _, fileName, fileLine, ok := runtime.Caller(skip); ok && !a.config.IgnoreFileLine {
+	_, fileName, fileLine, ok := runtime.Caller(skip)
+	return a.formatLog(time, msg, fileName, fileLine, ok)
+}
+
+func (a *Log) formatEntry(e *entry.Entry) string {
+	msg := e.Message
+	if len(e.Fields) > 0 {
+		msg = fmt.Sprintf("%s %s", msg, entry.FormatFields(e.Fields))
+	}
+	line := a.formatLog(e.Time, msg, e.Caller.File, e.Caller.Line, e.Caller.Defined())
+	if e.Stack != "" {
+		return fmt.Sprintf(messageFormatErrorDebug, line, e.Stack)
+	}
+	return line
+}
+
+func (a *Log) formatLog(time time.Time, msg string, fileName string, fileLine int, ok bool) string {
+	if ok && !a.config.IgnoreFileLine {
 		return fmt.Sprintf(
 			messageFormatWithFileLine,
 			time.Format(a.getTimeFormat()),
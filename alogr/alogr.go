@@ -0,0 +1,137 @@
+////////////////////////////////////////////////////////////////////////////////
+// Author:   Nikita Koryabkin
+// Email:    Nikita@Koryabk.in
+// Telegram: https://t.me/Apologiz
+////////////////////////////////////////////////////////////////////////////////
+
+package alogr
+
+import (
+	"fmt"
+	"runtime"
+
+	"github.com/go-logr/logr"
+	"github.com/mylockerteam/alog"
+	"github.com/mylockerteam/alog/entry"
+)
+
+const (
+	nameKey  = "logger"
+	errorKey = "error"
+	// missingValue is used when a key has no value, as in the other logr implementations
+	missingValue = "<no-value>"
+	// framesToCaller frames of this package between the logr.Logger method and runtime.Caller
+	framesToCaller = 2
+)
+
+// LogSink logr.LogSink which writes to the alog loggers
+type LogSink struct {
+	writer    alog.Writer
+	levels    []uint
+	name      string
+	fields    []entry.Field
+	callDepth int
+}
+
+// New creates logr.Logger on top of the writer. See NewLogSink for the levels
+func New(writer alog.Writer, levels ...uint) logr.Logger {
+	return logr.New(NewLogSink(writer, levels...))
+}
+
+// NewLogSink creates logr.LogSink on top of the writer.
+// levels[v] is the logger type for V(v), higher V-levels are disabled.
+// By default V(0) goes to the Info logger and V(1) to the Debug logger
+func NewLogSink(writer alog.Writer, levels ...uint) *LogSink {
+	if len(levels) == 0 {
+		levels = []uint{alog.Info, alog.Debug}
+	}
+	return &LogSink{writer: writer, levels: levels}
+}
+
+// Init receives the call depth of the logr library
+func (s *LogSink) Init(info logr.RuntimeInfo) {
+	s.callDepth = info.CallDepth
+}
+
+// Enabled reports whether the V-level is mapped to a configured logger
+func (s *LogSink) Enabled(level int) bool {
+	loggerType, ok := s.loggerType(level)
+	return ok && s.writer.Enabled(loggerType)
+}
+
+// Info records a message of the V-level
+func (s *LogSink) Info(level int, msg string, keysAndValues ...interface{}) {
+	if loggerType, ok := s.loggerType(level); ok {
+		s.write(loggerType, msg, keysAndValues)
+	}
+}
+
+// Error records a message to the Error logger
+func (s *LogSink) Error(err error, msg string, keysAndValues ...interface{}) {
+	if err != nil {
+		keysAndValues = append([]interface{}{errorKey, err.Error()}, keysAndValues...)
+	}
+	s.write(alog.Err, msg, keysAndValues)
+}
+
+// WithValues returns a sink which adds the key/value pairs to every message
+func (s *LogSink) WithValues(keysAndValues ...interface{}) logr.LogSink {
+	clone := *s
+	clone.fields = appendFields(s.fields[:len(s.fields):len(s.fields)], keysAndValues)
+	return &clone
+}
+
+// WithName returns a sink with the name appended to the logger name
+func (s *LogSink) WithName(name string) logr.LogSink {
+	clone := *s
+	if s.name == "" {
+		clone.name = name
+	} else {
+		clone.name = s.name + "/" + name
+	}
+	return &clone
+}
+
+// WithCallDepth returns a sink which skips additional frames when looking for the caller
+func (s *LogSink) WithCallDepth(depth int) logr.LogSink {
+	clone := *s
+	clone.callDepth += depth
+	return &clone
+}
+
+func (s *LogSink) loggerType(level int) (uint, bool) {
+	if level < 0 || level >= len(s.levels) {
+		return 0, false
+	}
+	return s.levels[level], true
+}
+
+func (s *LogSink) write(loggerType uint, msg string, keysAndValues []interface{}) {
+	e := &entry.Entry{
+		Level:   loggerType,
+		Message: msg,
+	}
+	if s.name != "" {
+		e.Fields = append(e.Fields, entry.Field{Key: nameKey, Value: s.name})
+	}
+	e.Fields = appendFields(append(e.Fields, s.fields...), keysAndValues)
+	if pc, fileName, fileLine, ok := runtime.Caller(s.callDepth + framesToCaller); ok {
+		e.Caller = entry.Caller{File: fileName, Line: fileLine}
+		if fn := runtime.FuncForPC(pc); fn != nil {
+			e.Caller.Function = fn.Name()
+		}
+	}
+	s.writer.Dispatch(e)
+}
+
+// appendFields converts logr key/value pairs to fields
+func appendFields(fields []entry.Field, keysAndValues []interface{}) []entry.Field {
+	for i := 0; i < len(keysAndValues); i += 2 {
+		var value interface{} = missingValue
+		if i+1 < len(keysAndValues) {
+			value = keysAndValues[i+1]
+		}
+		fields = append(fields, entry.Field{Key: fmt.Sprint(keysAndValues[i]), Value: value})
+	}
+	return fields
+}
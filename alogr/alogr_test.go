@@ -0,0 +1,158 @@
+////////////////////////////////////////////////////////////////////////////////
+// Author:   Nikita Koryabkin
+// Email:    Nikita@Koryabk.in
+// Telegram: https://t.me/Apologiz
+////////////////////////////////////////////////////////////////////////////////
+
+package alogr
+
+import (
+	"errors"
+	"io"
+	"reflect"
+	"runtime"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/mylockerteam/alog"
+	"github.com/mylockerteam/alog/entry"
+)
+
+const testMsg = "Hello, ALog!"
+
+type entryStrategy struct {
+	entries chan *entry.Entry
+}
+
+func (s *entryStrategy) Write(p []byte) (n int, err error) {
+	return len(p), nil
+}
+
+func (s *entryStrategy) WriteEntry(e *entry.Entry, p []byte) (n int, err error) {
+	s.entries <- e
+	return len(p), nil
+}
+
+func writerProvider(strategy *entryStrategy, loggerTypes ...uint) alog.Writer {
+	loggers := alog.Map{}
+	for _, loggerType := range loggerTypes {
+		loggers[loggerType] = &alog.Logger{
+			Channel:    make(chan string, 1),
+			Strategies: []io.Writer{strategy},
+		}
+	}
+	return alog.Create(&alog.Config{Loggers: loggers})
+}
+
+func TestLogSink_Enabled(t *testing.T) {
+	writer := writerProvider(&entryStrategy{}, alog.Info)
+	tests := []struct {
+		name   string
+		levels []uint
+		level  int
+		want   bool
+	}{
+		{
+			level: 0,
+			want:  true,
+		},
+		{
+			level: 1,
+			want:  false,
+		},
+		{
+			levels: []uint{alog.Info, alog.Wrn},
+			level:  1,
+			want:   false,
+		},
+		{
+			levels: []uint{alog.Info, alog.Info},
+			level:  1,
+			want:   true,
+		},
+		{
+			level: 2,
+			want:  false,
+		},
+		{
+			level: -1,
+			want:  false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NewLogSink(writer, tt.levels...).Enabled(tt.level); got != tt.want {
+				t.Errorf("LogSink.Enabled() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLogSink(t *testing.T) {
+	tests := []struct {
+		name   string
+		log    func(logger logr.Logger)
+		level  uint
+		fields []entry.Field
+	}{
+		{
+			log: func(logger logr.Logger) {
+				logger.Info(testMsg, "a", 1)
+			},
+			level:  alog.Info,
+			fields: []entry.Field{{Key: "a", Value: 1}},
+		},
+		{
+			log: func(logger logr.Logger) {
+				logger.V(1).Info(testMsg)
+				logger.WithName("first").WithName("second").WithValues("a", 1).Info(testMsg, "b")
+			},
+			level: alog.Info,
+			fields: []entry.Field{
+				{Key: nameKey, Value: "first/second"},
+				{Key: "a", Value: 1},
+				{Key: "b", Value: missingValue},
+			},
+		},
+		{
+			log: func(logger logr.Logger) {
+				logger.Error(errors.New("failure"), testMsg, "a", 1)
+			},
+			level:  alog.Err,
+			fields: []entry.Field{{Key: errorKey, Value: "failure"}, {Key: "a", Value: 1}},
+		},
+		{
+			log: func(logger logr.Logger) {
+				logger.Error(nil, testMsg)
+			},
+			level: alog.Err,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			strategy := &entryStrategy{entries: make(chan *entry.Entry, 1)}
+			logger := New(writerProvider(strategy, alog.Info, alog.Err))
+			_, fileName, _, _ := runtime.Caller(0)
+			tt.log(logger)
+			got := <-strategy.entries
+			if got.Level != tt.level || got.Message != testMsg || !reflect.DeepEqual(got.Fields, tt.fields) {
+				t.Errorf("LogSink = %+v, want level %v, fields %v", got, tt.level, tt.fields)
+			}
+			if got.Caller.File != fileName {
+				t.Errorf("LogSink caller = %v, want %v", got.Caller.File, fileName)
+			}
+		})
+	}
+}
+
+func TestLogSink_WithCallDepth(t *testing.T) {
+	strategy := &entryStrategy{entries: make(chan *entry.Entry, 1)}
+	logger := New(writerProvider(strategy, alog.Info))
+	_, fileName, fileLine, _ := runtime.Caller(0)
+	func() {
+		logger.WithCallDepth(1).Info(testMsg)
+	}()
+	if got := <-strategy.entries; got.Caller.File != fileName || got.Caller.Line != fileLine+3 {
+		t.Errorf("LogSink caller = %v, want %v:%d", got.Caller, fileName, fileLine+3)
+	}
+}
@@ -0,0 +1,199 @@
+////////////////////////////////////////////////////////////////////////////////
+// Author:   Nikita Koryabkin
+// Email:    Nikita@Koryabk.in
+// Telegram: https://t.me/Apologiz
+////////////////////////////////////////////////////////////////////////////////
+
+package alogtest
+
+import (
+	"io"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mylockerteam/alog"
+	"github.com/mylockerteam/alog/entry"
+)
+
+// Entries slice of the captured entries
+type Entries []*entry.Entry
+
+// Observer in-memory storage of the entries written by the logger
+type Observer struct {
+	mu      sync.Mutex
+	entries Entries
+}
+
+// observerStrategy captures entries of one logger type
+type observerStrategy struct {
+	observer   *Observer
+	loggerType uint
+}
+
+// tbStrategy forwards entries of one logger type to testing.TB
+type tbStrategy struct {
+	tb testing.TB
+}
+
+// New creates a synchronous logger which keeps entries in memory.
+// Without logger types Info, Warning and Error loggers are configured
+func New(loggerTypes ...uint) (alog.Writer, *Observer) {
+	observer := &Observer{}
+	return create(loggerTypes, func(loggerType uint) io.Writer {
+		return &observerStrategy{observer: observer, loggerType: loggerType}
+	}), observer
+}
+
+// NewTB creates a synchronous logger which writes to the log of the test
+func NewTB(tb testing.TB, loggerTypes ...uint) alog.Writer {
+	return create(loggerTypes, func(uint) io.Writer {
+		return &tbStrategy{tb: tb}
+	})
+}
+
+func create(loggerTypes []uint, strategy func(loggerType uint) io.Writer) alog.Writer {
+	if len(loggerTypes) == 0 {
+		loggerTypes = []uint{alog.Info, alog.Wrn, alog.Err}
+	}
+	loggers := alog.Map{}
+	for _, loggerType := range loggerTypes {
+		loggers[loggerType] = &alog.Logger{
+			Strategies: []io.Writer{strategy(loggerType)},
+		}
+	}
+	return alog.Create(&alog.Config{Loggers: loggers, Sync: true})
+}
+
+// All returns a copy of the captured entries
+func (o *Observer) All() Entries {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return append(Entries(nil), o.entries...)
+}
+
+// TakeAll returns the captured entries and clears the observer
+func (o *Observer) TakeAll() Entries {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	entries := o.entries
+	o.entries = nil
+	return entries
+}
+
+// Len returns the number of captured entries
+func (o *Observer) Len() int {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return len(o.entries)
+}
+
+func (o *Observer) add(e *entry.Entry) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.entries = append(o.entries, e)
+}
+
+// Filter returns the entries matching the function
+func (e Entries) Filter(match func(e *entry.Entry) bool) Entries {
+	var filtered Entries
+	for _, item := range e {
+		if match(item) {
+			filtered = append(filtered, item)
+		}
+	}
+	return filtered
+}
+
+// FilterLevel returns the entries of the logger type
+func (e Entries) FilterLevel(loggerType uint) Entries {
+	return e.Filter(func(item *entry.Entry) bool {
+		return item.Level == loggerType
+	})
+}
+
+// FilterMessage returns the entries with the message
+func (e Entries) FilterMessage(msg string) Entries {
+	return e.Filter(func(item *entry.Entry) bool {
+		return item.Message == msg
+	})
+}
+
+// FilterMessageSnippet returns the entries whose message contains the snippet
+func (e Entries) FilterMessageSnippet(snippet string) Entries {
+	return e.Filter(func(item *entry.Entry) bool {
+		return strings.Contains(item.Message, snippet)
+	})
+}
+
+// FilterField returns the entries having the field with the value
+func (e Entries) FilterField(key string, value interface{}) Entries {
+	return e.Filter(func(item *entry.Entry) bool {
+		for _, field := range item.Fields {
+			if field.Key == key && reflect.DeepEqual(field.Value, value) {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// AssertLen fails the test if the number of entries differs
+func (e Entries) AssertLen(tb testing.TB, want int) bool {
+	tb.Helper()
+	if len(e) != want {
+		tb.Errorf("got %d entries, want %d: %v", len(e), want, e.messages())
+		return false
+	}
+	return true
+}
+
+// AssertLogged fails the test if there is no entry of the logger type with the message
+func (e Entries) AssertLogged(tb testing.TB, loggerType uint, msg string) bool {
+	tb.Helper()
+	if len(e.FilterLevel(loggerType).FilterMessage(msg)) == 0 {
+		tb.Errorf("no %s entry %q in %v", alog.Name(loggerType), msg, e.messages())
+		return false
+	}
+	return true
+}
+
+// AssertNotLogged fails the test if there is an entry of the logger type with the message
+func (e Entries) AssertNotLogged(tb testing.TB, loggerType uint, msg string) bool {
+	tb.Helper()
+	if len(e.FilterLevel(loggerType).FilterMessage(msg)) != 0 {
+		tb.Errorf("unexpected %s entry %q", alog.Name(loggerType), msg)
+		return false
+	}
+	return true
+}
+
+func (e Entries) messages() []string {
+	messages := make([]string, 0, len(e))
+	for _, item := range e {
+		messages = append(messages, "["+item.LevelName+"] "+item.Message)
+	}
+	return messages
+}
+
+func (s *observerStrategy) Write(p []byte) (n int, err error) {
+	s.observer.add(&entry.Entry{
+		Level:     s.loggerType,
+		LevelName: alog.Name(s.loggerType),
+		Time:      time.Now(),
+		Message:   strings.TrimSuffix(string(p), "\n"),
+	})
+	return len(p), nil
+}
+
+func (s *observerStrategy) WriteEntry(e *entry.Entry, p []byte) (n int, err error) {
+	s.observer.add(e)
+	return len(p), nil
+}
+
+func (s *tbStrategy) Write(p []byte) (n int, err error) {
+	s.tb.Log(strings.TrimSuffix(string(p), "\n"))
+	return len(p), nil
+}
@@ -0,0 +1,125 @@
+////////////////////////////////////////////////////////////////////////////////
+// Author:   Nikita Koryabkin
+// Email:    Nikita@Koryabk.in
+// Telegram: https://t.me/Apologiz
+////////////////////////////////////////////////////////////////////////////////
+
+package alogtest
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"runtime"
+	"testing"
+
+	"github.com/mylockerteam/alog"
+)
+
+const testMsg = "Hello, ALog!"
+
+// recorder testing.TB which keeps failures instead of failing the test
+type recorder struct {
+	testing.TB
+	logs   []string
+	errors []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Log(args ...interface{}) {
+	r.logs = append(r.logs, fmt.Sprint(args...))
+}
+
+func (r *recorder) Errorf(format string, args ...interface{}) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func TestNew(t *testing.T) {
+	writer, observer := New()
+	_, fileName, fileLine, _ := runtime.Caller(0)
+	writer.Info(testMsg)
+	writer.Warning(testMsg)
+	writer.Error(errors.New(testMsg))
+	slog.New(alog.NewSlogHandler(writer)).Info(testMsg, "a", 1)
+	_, _ = writer.GetLoggerInterfaceByType(alog.Wrn).Write([]byte(testMsg + "\n"))
+
+	entries := observer.All()
+	entries.AssertLen(t, 5)
+	entries.AssertLogged(t, alog.Info, testMsg)
+	entries.AssertLogged(t, alog.Wrn, testMsg)
+	entries.AssertLogged(t, alog.Err, testMsg)
+	entries.FilterField("a", int64(1)).AssertLen(t, 1)
+	entries.FilterLevel(alog.Wrn).AssertLen(t, 2)
+	entries.FilterMessageSnippet("ALog").AssertLen(t, 5)
+	if got := entries[0].Caller; got.File != fileName || got.Line != fileLine+1 {
+		t.Errorf("New() caller = %v, want %s:%d", got, fileName, fileLine+1)
+	}
+	if got := observer.TakeAll(); len(got) != 5 || observer.Len() != 0 {
+		t.Errorf("Observer.TakeAll() = %d entries, left %d", len(got), observer.Len())
+	}
+}
+
+func TestNew_loggerTypes(t *testing.T) {
+	writer, observer := New(alog.Err)
+	if writer.Enabled(alog.Info) || !writer.Enabled(alog.Err) {
+		t.Errorf("New() configured wrong loggers")
+	}
+	writer.Error(errors.New(testMsg))
+	observer.All().AssertLen(t, 1)
+}
+
+func TestEntries_Assert(t *testing.T) {
+	writer, observer := New()
+	writer.Info(testMsg)
+	tests := []struct {
+		name   string
+		assert func(tb testing.TB) bool
+		want   bool
+	}{
+		{
+			assert: func(tb testing.TB) bool {
+				return observer.All().AssertLen(tb, 2)
+			},
+			want: false,
+		},
+		{
+			assert: func(tb testing.TB) bool {
+				return observer.All().AssertLogged(tb, alog.Err, testMsg)
+			},
+			want: false,
+		},
+		{
+			assert: func(tb testing.TB) bool {
+				return observer.All().AssertNotLogged(tb, alog.Info, testMsg)
+			},
+			want: false,
+		},
+		{
+			assert: func(tb testing.TB) bool {
+				return observer.All().AssertNotLogged(tb, alog.Err, testMsg)
+			},
+			want: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &recorder{TB: t}
+			if got := tt.assert(r); got != tt.want || (len(r.errors) == 0) != tt.want {
+				t.Errorf("assert = %v, want %v, errors %v", got, tt.want, r.errors)
+			}
+		})
+	}
+}
+
+func TestNewTB(t *testing.T) {
+	r := &recorder{TB: t}
+	writer := NewTB(r)
+	writer.Info(testMsg)
+	if len(r.logs) != 1 {
+		t.Fatalf("NewTB() logged %d lines, want 1", len(r.logs))
+	}
+	if want := fmt.Sprintf(";%s", testMsg); r.logs[0][len(r.logs[0])-len(want):] != want {
+		t.Errorf("NewTB() = %v, want suffix %v", r.logs[0], want)
+	}
+}
@@ -0,0 +1,81 @@
+////////////////////////////////////////////////////////////////////////////////
+// Author:   Nikita Koryabkin
+// Email:    Nikita@Koryabk.in
+// Telegram: https://t.me/Apologiz
+////////////////////////////////////////////////////////////////////////////////
+
+package entry
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Field key-value pair attached to the entry
+type Field struct {
+	Key   string
+	Value interface{}
+}
+
+// Caller position of the code that made the entry
+type Caller struct {
+	File     string
+	Line     int
+	Function string
+}
+
+// Entry structured record which is passed to the strategies together with the formatted line
+type Entry struct {
+	Level     uint
+	LevelName string
+	Time      time.Time
+	Caller    Caller
+	Message   string
+	Fields    []Field
+	Stack     string
+}
+
+// Writer interface for strategies that need the structured entry.
+// Loggers call WriteEntry instead of Write when the strategy implements it
+type Writer interface {
+	io.Writer
+	WriteEntry(e *Entry, p []byte) (n int, err error)
+}
+
+// Defined reports whether the caller position is known
+func (c Caller) Defined() bool {
+	return c.File != ""
+}
+
+func (c Caller) String() string {
+	if !c.Defined() {
+		return ""
+	}
+	return fmt.Sprintf("%s:%d", c.File, c.Line)
+}
+
+// FormatFields returns fields in the key=value form separated by spaces.
+// Values containing spaces, quotes or equal signs are quoted
+func FormatFields(fields []Field) string {
+	var b strings.Builder
+	for i, f := range fields {
+		if i > 0 {
+			b.WriteByte(' ')
+		}
+		b.WriteString(f.Key)
+		b.WriteByte('=')
+		b.WriteString(formatValue(f.Value))
+	}
+	return b.String()
+}
+
+func formatValue(value interface{}) string {
+	v := fmt.Sprint(value)
+	if v == "" || strings.ContainsAny(v, " \t\r\n\"=") {
+		return strconv.Quote(v)
+	}
+	return v
+}
@@ -0,0 +1,63 @@
+////////////////////////////////////////////////////////////////////////////////
+// Author:   Nikita Koryabkin
+// Email:    Nikita@Koryabk.in
+// Telegram: https://t.me/Apologiz
+////////////////////////////////////////////////////////////////////////////////
+
+package entry
+
+import (
+	"testing"
+)
+
+func TestCaller_String(t *testing.T) {
+	tests := []struct {
+		name   string
+		caller Caller
+		want   string
+	}{
+		{
+			caller: Caller{File: "/app/main.go", Line: 10},
+			want:   "/app/main.go:10",
+		},
+		{
+			caller: Caller{},
+			want:   "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.caller.String(); got != tt.want {
+				t.Errorf("Caller.String() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatFields(t *testing.T) {
+	tests := []struct {
+		name   string
+		fields []Field
+		want   string
+	}{
+		{
+			fields: nil,
+			want:   "",
+		},
+		{
+			fields: []Field{{Key: "a", Value: 1}, {Key: "b", Value: "c"}},
+			want:   "a=1 b=c",
+		},
+		{
+			fields: []Field{{Key: "a", Value: "b c"}, {Key: "d", Value: ""}, {Key: "e", Value: "f=g"}},
+			want:   `a="b c" d="" e="f=g"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FormatFields(tt.fields); got != tt.want {
+				t.Errorf("FormatFields() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
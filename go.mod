@@ -1,8 +1,9 @@
 module github.com/zevst/alog
 
-go 1.12
+go 1.21
 
 require (
+	github.com/go-logr/logr v1.4.2
 	github.com/golang/mock v1.2.0
 	github.com/zevst/mailSender v0.0.0-20190315220807-36ac1ab8418d
 	github.com/spf13/afero v1.2.1
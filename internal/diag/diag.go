@@ -0,0 +1,33 @@
+////////////////////////////////////////////////////////////////////////////////
+// Author:   Nikita Koryabkin
+// Email:    Nikita@Koryabk.in
+// Telegram: https://t.me/Apologiz
+////////////////////////////////////////////////////////////////////////////////
+
+// Package diag writes the strategies' own messages and errors to stderr.
+// It doesn't use the standard logger, because it may be redirected into alog
+// and the messages would loop back into the failing strategy
+package diag
+
+import (
+	"io"
+	"log"
+	"os"
+)
+
+var logger = log.New(os.Stderr, "", log.LstdFlags)
+
+// Println writes the message like log.Println
+func Println(v ...interface{}) {
+	logger.Println(v...)
+}
+
+// Printf writes the message like log.Printf
+func Printf(format string, v ...interface{}) {
+	logger.Printf(format, v...)
+}
+
+// SetOutput replaces stderr, e.g. in tests
+func SetOutput(w io.Writer) {
+	logger.SetOutput(w)
+}
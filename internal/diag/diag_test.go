@@ -0,0 +1,42 @@
+////////////////////////////////////////////////////////////////////////////////
+// Author:   Nikita Koryabkin
+// Email:    Nikita@Koryabk.in
+// Telegram: https://t.me/Apologiz
+////////////////////////////////////////////////////////////////////////////////
+
+package diag
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestPrintln(t *testing.T) {
+	tests := []struct {
+		name  string
+		write func()
+		want  string
+	}{
+		{
+			write: func() { Println("disk", "is full") },
+			want:  "disk is full\n",
+		},
+		{
+			write: func() { Printf("%d bytes free", 10) },
+			want:  "10 bytes free\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := &bytes.Buffer{}
+			SetOutput(out)
+			defer SetOutput(os.Stderr)
+			tt.write()
+			if got := out.String(); !strings.HasSuffix(got, tt.want) {
+				t.Errorf("output = %q, want suffix %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -10,7 +10,10 @@ import (
 	"errors"
 	"fmt"
 	"io"
-	"log"
+	"sync"
+	"sync/atomic"
+
+	"github.com/mylockerteam/alog/entry"
 )
 
 // Logger types
@@ -18,22 +21,36 @@ const (
 	Info uint = iota
 	Wrn
 	Err
+	Debug
 )
 
+// levels logger types from the most verbose
+var levels = []uint{Debug, Info, Wrn, Err}
+
 // Logger logger structure which includes a channel and a slice strategies
 type Logger struct {
 	io.Writer
 	Channel    chan string
 	Strategies []io.Writer
+	records    chan record
+	mu         *sync.Mutex
+	started    uint32
+}
+
+// record entry together with the line formatted for plain strategies
+type record struct {
+	entry *entry.Entry
+	line  string
 }
 
 // Map mapping for type:logger
 type Map map[uint]*Logger
 
 var loggerName = map[uint]string{
-	Info: "Info",
-	Wrn:  "Warning",
-	Err:  "Error",
+	Info:  "Info",
+	Wrn:   "Warning",
+	Err:   "Error",
+	Debug: "Debug",
 }
 
 // Name returns a name for the logger.
@@ -42,11 +59,36 @@ func Name(code uint) string {
 	return loggerName[code]
 }
 
+// levelRank returns the verbosity order of the logger type, false for custom types
+func levelRank(code uint) (int, bool) {
+	for i, t := range levels {
+		if t == code {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// running reports whether the logger is already started
+func (l *Logger) running() bool {
+	return atomic.LoadUint32(&l.started) == 1
+}
+
 // Writer interface for informational messages
 func (l *Logger) Write(p []byte) (n int, err error) {
 	if l == nil || isClosedCh(l.Channel) {
 		return 0, errors.New("the channel was closed for recording")
 	}
+	if l.mu != nil {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		l.writeMessage(string(p))
+		return len(p), nil
+	}
+	if l.records != nil {
+		l.records <- record{line: string(p)}
+		return len(p), nil
+	}
 	l.Channel <- string(p)
 	return len(p), nil
 }
@@ -60,17 +102,88 @@ func isClosedCh(ch <-chan string) bool {
 	}
 }
 
-//Reader for messages
+// start creates the channel for entries and runs the reader.
+// A synchronous logger gets a mutex instead and has no reader. The logger is started once,
+// e.g. when it is registered for several types
+func (l *Logger) start(synchronous bool) {
+	if !atomic.CompareAndSwapUint32(&l.started, 0, 1) {
+		return
+	}
+	if synchronous {
+		l.mu = new(sync.Mutex)
+		return
+	}
+	l.records = make(chan record, cap(l.Channel))
+	go l.Reader()
+}
+
+// send passes the record to the reader. Without the entries channel
+// only the formatted line is sent
+func (l *Logger) send(r record) {
+	switch {
+	case l.mu != nil:
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		l.writeRecord(r)
+	case l.records != nil:
+		l.records <- r
+	default:
+		l.Channel <- r.line
+	}
+}
+
+//Reader for messages. After the channel is closed the queued entries are written
 func (l *Logger) Reader() {
-	for msg := range l.Channel {
-		l.writeMessage(msg)
+	for {
+		select {
+		case msg, ok := <-l.Channel:
+			if !ok {
+				l.drain()
+				return
+			}
+			l.writeMessage(msg)
+		case r := <-l.records:
+			l.writeRecord(r)
+		}
+	}
+}
+
+// drain writes the records queued before the channel was closed
+func (l *Logger) drain() {
+	for {
+		select {
+		case r := <-l.records:
+			l.writeRecord(r)
+		default:
+			return
+		}
 	}
 }
 
 func (l *Logger) writeMessage(msg string) {
 	for _, s := range l.Strategies {
 		if n, err := s.Write([]byte(msg)); err != nil {
-			log.Println(fmt.Sprintf("%d characters have been written. %s", n, err.Error()))
+			internalLog.Println(fmt.Sprintf("%d characters have been written. %s", n, err.Error()))
+		}
+	}
+}
+
+// writeRecord writes the record, the lines written through io.Writer have no entry
+func (l *Logger) writeRecord(r record) {
+	if r.entry == nil {
+		l.writeMessage(r.line)
+		return
+	}
+	for _, s := range l.Strategies {
+		var n int
+		var err error
+		if w, ok := s.(entry.Writer); ok {
+			n, err = w.WriteEntry(r.entry, []byte(r.line))
+		} else {
+			n, err = s.Write([]byte(r.line))
+		}
+		if err != nil {
+			internalLog.Println(fmt.Sprintf("%d characters have been written. %s", n, err.Error()))
 		}
 	}
 }
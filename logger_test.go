@@ -10,9 +10,23 @@ import (
 	"io"
 	"testing"
 
+	"github.com/mylockerteam/alog/entry"
 	"github.com/mylockerteam/alog/strategy/file"
 )
 
+type entryStrategy struct {
+	entries chan *entry.Entry
+}
+
+func (s *entryStrategy) Write(p []byte) (n int, err error) {
+	return len(p), nil
+}
+
+func (s *entryStrategy) WriteEntry(e *entry.Entry, p []byte) (n int, err error) {
+	s.entries <- e
+	return len(p), nil
+}
+
 type argsLoggerWriteMessage struct {
 	msg string
 }
@@ -154,6 +168,24 @@ func TestLogger_reader(t *testing.T) {
 	}
 }
 
+func TestLogger_writeRecord(t *testing.T) {
+	strategy := &entryStrategy{entries: make(chan *entry.Entry, 1)}
+	l := &Logger{
+		Channel: make(chan string, 1),
+		Strategies: []io.Writer{
+			strategy,
+			file.Get(""),
+		},
+	}
+	l.start(false)
+	defer close(l.Channel)
+	want := &entry.Entry{Message: testMsg}
+	l.send(record{entry: want, line: testMsg})
+	if got := <-strategy.entries; got != want {
+		t.Errorf("Logger.writeRecord() = %v, want %v", got, want)
+	}
+}
+
 func TestName(t *testing.T) {
 	type args struct {
 		code uint
@@ -181,6 +213,12 @@ func TestName(t *testing.T) {
 			},
 			want: "Error",
 		},
+		{
+			args: args{
+				code: Debug,
+			},
+			want: "Debug",
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -190,3 +228,49 @@ func TestName(t *testing.T) {
 		})
 	}
 }
+
+func TestLogger_order(t *testing.T) {
+	strategy := &entryStrategy{entries: make(chan *entry.Entry, 3)}
+	out := &lineStrategy{lines: make(chan string, 3)}
+	l := &Logger{
+		Channel:    make(chan string, 3),
+		Strategies: []io.Writer{strategy, out},
+	}
+	// the channel is closed before the reader runs, the queued records must be written in order
+	l.records = make(chan record, 3)
+	l.started = 1
+	l.send(record{entry: &entry.Entry{Message: "first"}, line: "first"})
+	if _, err := l.Write([]byte("second")); err != nil {
+		t.Fatalf("Logger.Write() error = %v", err)
+	}
+	l.send(record{entry: &entry.Entry{Message: "third"}, line: "third"})
+	close(l.Channel)
+	l.start(false)
+	l.Reader()
+	for _, want := range []string{"first", "second", "third"} {
+		if got := <-out.lines; got != want {
+			t.Errorf("Logger.Reader() wrote %v, want %v", got, want)
+		}
+	}
+}
+
+func TestLogger_start(t *testing.T) {
+	l := &Logger{Channel: make(chan string, 1)}
+	l.start(false)
+	records := l.records
+	l.start(false)
+	defer close(l.Channel)
+	if l.records != records {
+		t.Errorf("Logger.start() restarted the logger")
+	}
+}
+
+// lineStrategy passes the written lines to the channel
+type lineStrategy struct {
+	lines chan string
+}
+
+func (s *lineStrategy) Write(p []byte) (n int, err error) {
+	s.lines <- string(p)
+	return len(p), nil
+}
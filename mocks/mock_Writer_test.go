@@ -0,0 +1,31 @@
+////////////////////////////////////////////////////////////////////////////////
+// Author:   Nikita Koryabkin
+// Email:    Nikita@Koryabk.in
+// Telegram: https://t.me/Apologiz
+////////////////////////////////////////////////////////////////////////////////
+
+package mocks
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/mylockerteam/alog"
+)
+
+var _ alog.Writer = (*MockWriter)(nil)
+
+func TestMockWriter(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	w := NewMockWriter(ctrl)
+	err := errors.New("failed")
+	gomock.InOrder(
+		w.EXPECT().Infof("%s %d", "a", 1).Return(w),
+		w.EXPECT().Error(err).Return(w),
+	)
+	if got := w.Infof("%s %d", "a", 1).Error(err); got != w {
+		t.Errorf("MockWriter chain = %v, want %v", got, w)
+	}
+}
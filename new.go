@@ -0,0 +1,185 @@
+////////////////////////////////////////////////////////////////////////////////
+// Author:   Nikita Koryabkin
+// Email:    Nikita@Koryabk.in
+// Telegram: https://t.me/Apologiz
+////////////////////////////////////////////////////////////////////////////////
+
+package alog
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/mylockerteam/alog/entry"
+)
+
+// maxChanBuffer limits the buffer of the channels. make allocates the buffer at once,
+// so 1<<20 strings take 16 MiB per logger and sizes beyond the memory make it panic
+const maxChanBuffer = 1 << 20
+
+var (
+	errUnknownLoggerType  = errors.New("unknown logger type")
+	errLoggerNotDefined   = errors.New("logger is not defined")
+	errStrategyNotDefined = errors.New("strategy is not defined")
+	errOutputNotDefined   = errors.New("output is not defined")
+	errChanBuffer         = errors.New("channel buffer is too large")
+	errSampling           = errors.New("invalid sampling")
+	errBelowLevel         = errors.New("logger type is below the level")
+	errLoggerReused       = errors.New("logger is already registered")
+	errLoggerRunning      = errors.New("logger is already running")
+)
+
+// New creates the logger of the options: Info and above write the formatted messages to stdout
+// through buffered channels. Copies of the WithLogger loggers replace the ones of the level, those without
+// strategies share the strategy of the output, written under a lock, and asynchronous ones without a channel
+// get a buffered one.
+// It fails on unknown logger types, nil loggers, outputs and strategies, loggers below the level,
+// registered twice or already running, too large buffers and invalid sampling
+func New(opts ...Option) (Writer, error) {
+	o := &options{
+		level:  Info,
+		output: os.Stdout,
+		strategy: func(out io.Writer) io.Writer {
+			return out
+		},
+		chanBuffer: defaultChanBuffer,
+		timeFormat: time.RFC3339Nano,
+	}
+	return o.apply(opts).create()
+}
+
+// WithLogger configures the logger of the type instead of the one of the level.
+// The logger is copied, so it must not be running or passed twice
+func WithLogger(loggerType uint, l *Logger) Option {
+	return func(o *options) {
+		if o.loggers == nil {
+			o.loggers = Map{}
+		}
+		o.loggers[loggerType] = l
+	}
+}
+
+// config validates the options and returns the config with the defaults filled
+func (o *options) config() (*Config, error) {
+	if err := o.validate(); err != nil {
+		return nil, err
+	}
+	if o.strategies == nil {
+		o.strategies = []io.Writer{o.strategy(o.output)}
+	}
+	shared := make([]io.Writer, len(o.strategies))
+	for i, s := range o.strategies {
+		shared[i] = &lockedWriter{w: s}
+	}
+	loggers := Map{}
+	for _, loggerType := range levelsFrom(o.level) {
+		loggers[loggerType] = &Logger{}
+	}
+	registered := map[*Logger]uint{}
+	for loggerType, l := range o.loggers {
+		if err := validateLogger(loggerType, l); err != nil {
+			return nil, err
+		}
+		if _, ok := loggers[loggerType]; !ok {
+			return nil, fmt.Errorf("%s logger: %w %s", Name(loggerType), errBelowLevel, Name(o.level))
+		}
+		if other, ok := registered[l]; ok {
+			return nil, fmt.Errorf("%s logger: %w for %s", Name(loggerType), errLoggerReused, Name(other))
+		}
+		registered[l] = loggerType
+		loggers[loggerType] = &Logger{Writer: l.Writer, Channel: l.Channel, Strategies: append([]io.Writer(nil), l.Strategies...)}
+	}
+	for _, l := range loggers {
+		if len(l.Strategies) == 0 {
+			l.Strategies = shared
+		}
+		if l.Channel == nil && !o.sync {
+			l.Channel = make(chan string, o.chanBuffer)
+		}
+	}
+	level := o.level
+	return &Config{
+		Loggers:    loggers,
+		MinLevel:   &level,
+		TimeFormat: o.timeFormat,
+		Sync:       o.sync,
+		StackTrace: o.stackTrace,
+		Sampling:   o.sampling,
+	}, nil
+}
+
+func (o *options) validate() error {
+	if levelsFrom(o.level) == nil {
+		return fmt.Errorf("level %d: %w", o.level, errUnknownLoggerType)
+	}
+	for _, loggerType := range o.stackTrace {
+		if Name(loggerType) == "" {
+			return fmt.Errorf("stack trace of %d: %w", loggerType, errUnknownLoggerType)
+		}
+	}
+	if o.strategies == nil && o.output == nil {
+		return errOutputNotDefined
+	}
+	for i, s := range o.strategies {
+		if s == nil {
+			return fmt.Errorf("strategy %d: %w", i, errStrategyNotDefined)
+		}
+	}
+	if o.chanBuffer > maxChanBuffer {
+		return fmt.Errorf("%w: %d, at most %d", errChanBuffer, o.chanBuffer, maxChanBuffer)
+	}
+	if s := o.sampling; s != nil {
+		if s.Tick <= 0 {
+			return fmt.Errorf("%w: tick %v must be positive", errSampling, s.Tick)
+		}
+		if s.Initial < 0 || s.Thereafter < 0 {
+			return fmt.Errorf("%w: initial %d and thereafter %d must not be negative", errSampling, s.Initial, s.Thereafter)
+		}
+	}
+	return nil
+}
+
+func validateLogger(loggerType uint, l *Logger) error {
+	if Name(loggerType) == "" {
+		return fmt.Errorf("logger %d: %w", loggerType, errUnknownLoggerType)
+	}
+	if l == nil {
+		return fmt.Errorf("%s logger: %w", Name(loggerType), errLoggerNotDefined)
+	}
+	if l.running() {
+		return fmt.Errorf("%s logger: %w", Name(loggerType), errLoggerRunning)
+	}
+	for i, s := range l.Strategies {
+		if s == nil {
+			return fmt.Errorf("%s logger strategy %d: %w", Name(loggerType), i, errStrategyNotDefined)
+		}
+	}
+	return nil
+}
+
+// lockedWriter serializes writes to the strategy shared by the readers of several loggers
+type lockedWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// Write writes the line under the lock
+func (l *lockedWriter) Write(p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.w.Write(p)
+}
+
+// WriteEntry writes the entry under the lock, plain strategies get the line
+func (l *lockedWriter) WriteEntry(e *entry.Entry, p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if w, ok := l.w.(entry.Writer); ok {
+		return w.WriteEntry(e, p)
+	}
+	return l.w.Write(p)
+}
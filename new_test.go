@@ -0,0 +1,168 @@
+////////////////////////////////////////////////////////////////////////////////
+// Author:   Nikita Koryabkin
+// Email:    Nikita@Koryabk.in
+// Telegram: https://t.me/Apologiz
+////////////////////////////////////////////////////////////////////////////////
+
+package alog
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNew(t *testing.T) {
+	shared, running := &Logger{}, &Logger{}
+	running.start(true)
+	tests := []struct {
+		name    string
+		opts    []Option
+		wantErr error
+		want    string
+	}{
+		{
+			opts: []Option{WithLevel(42)},
+			want: "level 42: unknown logger type",
+		},
+		{
+			opts: []Option{WithStackTrace(Err, 7)},
+			want: "stack trace of 7: unknown logger type",
+		},
+		{
+			opts:    []Option{WithOutput(nil)},
+			wantErr: errOutputNotDefined,
+		},
+		{
+			opts: []Option{WithStrategies(&bytes.Buffer{}, nil)},
+			want: "strategy 1: strategy is not defined",
+		},
+		{
+			opts: []Option{WithChanBuffer(^uint(0))},
+			want: fmt.Sprintf("channel buffer is too large: %d, at most %d", ^uint(0), maxChanBuffer),
+		},
+		{
+			opts: []Option{WithSampling(&Sampling{Initial: 1})},
+			want: "invalid sampling: tick 0s must be positive",
+		},
+		{
+			opts: []Option{WithSampling(&Sampling{Tick: time.Second, Thereafter: -1})},
+			want: "invalid sampling: initial 0 and thereafter -1 must not be negative",
+		},
+		{
+			opts: []Option{WithLogger(9, &Logger{})},
+			want: "logger 9: unknown logger type",
+		},
+		{
+			opts:    []Option{WithLogger(Wrn, nil)},
+			wantErr: errLoggerNotDefined,
+		},
+		{
+			opts: []Option{WithLogger(Err, &Logger{Strategies: []io.Writer{nil}})},
+			want: "Error logger strategy 0: strategy is not defined",
+		},
+		{
+			opts: []Option{WithLogger(Debug, &Logger{})},
+			want: "Debug logger: logger type is below the level Info",
+		},
+		{
+			opts:    []Option{WithLogger(Wrn, shared), WithLogger(Err, shared)},
+			wantErr: errLoggerReused,
+		},
+		{
+			opts: []Option{WithLogger(Err, running)},
+			want: "Error logger: logger is already running",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := New(tt.opts...)
+			if err == nil || got != nil {
+				t.Fatalf("New() = %v, error = %v", got, err)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("New() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.want != "" && err.Error() != tt.want {
+				t.Errorf("New() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestNew_defaults(t *testing.T) {
+	w, err := New()
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	config := w.(*Log).config
+	if len(config.Loggers) != 3 || config.Loggers[Debug] != nil || config.TimeFormat != time.RFC3339Nano {
+		t.Errorf("New() config = %+v", config)
+	}
+	for loggerType, l := range config.Loggers {
+		if cap(l.Channel) != defaultChanBuffer || len(l.Strategies) != 1 || l.records == nil {
+			t.Errorf("New() %s logger = %+v", Name(loggerType), l)
+		}
+	}
+}
+
+func TestNew_loggers(t *testing.T) {
+	out, own := &bytes.Buffer{}, &bytes.Buffer{}
+	custom := &Logger{Strategies: []io.Writer{own}}
+	w, err := New(
+		WithOutput(out),
+		WithSync(true),
+		WithLevel(Debug),
+		WithLogger(Err, custom),
+	)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	for _, loggerType := range levels {
+		if !w.Enabled(loggerType) {
+			t.Errorf("New() didn't configure the %s logger", Name(loggerType))
+		}
+	}
+	w.Debug(testMsg).Error(errors.New(testMsg))
+	if got := out.String(); !strings.HasPrefix(got, "[Debug] ") || !strings.HasSuffix(got, ";"+testMsg+"\n") {
+		t.Errorf("New() Debug logger wrote %q", got)
+	}
+	if got := own.String(); !strings.HasPrefix(got, "[Error] ") {
+		t.Errorf("New() Error logger wrote %q", got)
+	}
+	l := w.(*Log).config.Loggers[Err]
+	if l == custom || custom.running() || custom.mu != nil {
+		t.Errorf("New() changed the logger of WithLogger")
+	}
+	if l.Channel != nil || l.mu == nil {
+		t.Errorf("New() synchronous logger got a channel")
+	}
+}
+
+func TestNew_sharedOutput(t *testing.T) {
+	out := &bytes.Buffer{}
+	w, err := New(WithOutput(out), WithLevel(Debug), WithChanBuffer(0))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	const count = 100
+	for i := 0; i < count; i++ {
+		w.Debug(testMsg).Info(testMsg).Warning(testMsg).Error(errors.New(testMsg))
+	}
+	shared := w.(*Log).config.Loggers[Info].Strategies[0].(*lockedWriter)
+	for deadline := time.Now().Add(5 * time.Second); ; time.Sleep(time.Millisecond) {
+		shared.mu.Lock()
+		got := strings.Count(out.String(), ";"+testMsg+"\n")
+		shared.mu.Unlock()
+		if got == 4*count {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("New() wrote %d lines, want %d", got, 4*count)
+		}
+	}
+}
@@ -0,0 +1,165 @@
+////////////////////////////////////////////////////////////////////////////////
+// Author:   Nikita Koryabkin
+// Email:    Nikita@Koryabk.in
+// Telegram: https://t.me/Apologiz
+////////////////////////////////////////////////////////////////////////////////
+
+package alog
+
+import (
+	"io"
+	"os"
+	"time"
+
+	"github.com/mylockerteam/alog/strategy/console"
+	"github.com/mylockerteam/alog/strategy/jsonl"
+)
+
+const defaultChanBuffer = 1024
+
+// Option overrides a part of the preset
+type Option func(o *options)
+
+type options struct {
+	level      uint
+	loggers    Map
+	output     io.Writer
+	strategy   func(out io.Writer) io.Writer
+	strategies []io.Writer
+	sync       bool
+	chanBuffer uint
+	stackTrace []uint
+	sampling   *Sampling
+	timeFormat string
+}
+
+// Development logger: Debug and above in the colored console on stderr with full caller paths,
+// messages are written synchronously and warnings and errors get the stack trace.
+// Like regexp.MustCompile it panics on invalid options, see NewDevelopment for the error
+func Development(opts ...Option) Writer {
+	return must(NewDevelopment(opts...))
+}
+
+// NewDevelopment creates the Development logger and fails on invalid options, see New
+func NewDevelopment(opts ...Option) (Writer, error) {
+	o := &options{
+		level:  Debug,
+		output: os.Stderr,
+		strategy: func(out io.Writer) io.Writer {
+			return console.Get(console.WithWriter(out), console.WithFullCaller())
+		},
+		sync:       true,
+		stackTrace: []uint{Wrn, Err},
+		timeFormat: time.RFC3339Nano,
+	}
+	return o.apply(opts).create()
+}
+
+// Production logger: Info and above as JSON lines on stdout, repeated messages are sampled,
+// only errors get the stack trace. Messages are buffered in the channels and written by the readers.
+// Like regexp.MustCompile it panics on invalid options, see NewProduction for the error
+func Production(opts ...Option) Writer {
+	return must(NewProduction(opts...))
+}
+
+// NewProduction creates the Production logger and fails on invalid options, see New
+func NewProduction(opts ...Option) (Writer, error) {
+	o := &options{
+		level:      Info,
+		output:     os.Stdout,
+		strategy:   jsonl.Get,
+		chanBuffer: defaultChanBuffer,
+		stackTrace: []uint{Err},
+		sampling:   &Sampling{Tick: time.Second, Initial: 100, Thereafter: 100},
+		timeFormat: time.RFC3339Nano,
+	}
+	return o.apply(opts).create()
+}
+
+// WithLevel configures loggers of the type and the less verbose ones
+func WithLevel(loggerType uint) Option {
+	return func(o *options) {
+		o.level = loggerType
+	}
+}
+
+// WithOutput sets the output of the preset strategy
+func WithOutput(out io.Writer) Option {
+	return func(o *options) {
+		o.output = out
+	}
+}
+
+// WithStrategies replaces the preset strategy. The strategies are shared by the loggers of all types,
+// so the writes to them are serialized
+func WithStrategies(strategies ...io.Writer) Option {
+	return func(o *options) {
+		o.strategies = strategies
+	}
+}
+
+// WithSync enables or disables synchronous writes
+func WithSync(sync bool) Option {
+	return func(o *options) {
+		o.sync = sync
+	}
+}
+
+// WithChanBuffer sets the buffer of the channels of asynchronous loggers
+func WithChanBuffer(size uint) Option {
+	return func(o *options) {
+		o.chanBuffer = size
+	}
+}
+
+// WithStackTrace sets the logger types whose messages get the stack trace, none without arguments
+func WithStackTrace(loggerTypes ...uint) Option {
+	return func(o *options) {
+		o.stackTrace = loggerTypes
+	}
+}
+
+// WithSampling sets the sampling, nil disables it
+func WithSampling(sampling *Sampling) Option {
+	return func(o *options) {
+		o.sampling = sampling
+	}
+}
+
+// WithTimeFormat sets the time format of the formatted messages
+func WithTimeFormat(format string) Option {
+	return func(o *options) {
+		o.timeFormat = format
+	}
+}
+
+func (o *options) apply(opts []Option) *options {
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// create validates the options and creates the logger of them
+func (o *options) create() (Writer, error) {
+	config, err := o.config()
+	if err != nil {
+		return nil, err
+	}
+	return Create(config), nil
+}
+
+func must(w Writer, err error) Writer {
+	if err != nil {
+		panic(err)
+	}
+	return w
+}
+
+// levelsFrom returns the logger type and the less verbose ones
+func levelsFrom(loggerType uint) []uint {
+	if i, ok := levelRank(loggerType); ok {
+		return levels[i:]
+	}
+	return nil
+}
@@ -0,0 +1,267 @@
+////////////////////////////////////////////////////////////////////////////////
+// Author:   Nikita Koryabkin
+// Email:    Nikita@Koryabk.in
+// Telegram: https://t.me/Apologiz
+////////////////////////////////////////////////////////////////////////////////
+
+package alog
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mylockerteam/alog/entry"
+)
+
+func TestDevelopment(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     []Option
+		write    func(w Writer)
+		contains []string
+		excludes []string
+	}{
+		{
+			write: func(w Writer) {
+				w.Debug(testMsg)
+			},
+			contains: []string{"DEBUG", "/preset_test.go:", testMsg},
+			excludes: []string{"goroutine"},
+		},
+		{
+			write: func(w Writer) {
+				w.Warning(testMsg)
+			},
+			contains: []string{"WARNING", testMsg, "    goroutine"},
+		},
+		{
+			opts: []Option{WithLevel(Info), WithStackTrace()},
+			write: func(w Writer) {
+				w.Debug("hidden")
+				w.Error(errors.New(testMsg))
+			},
+			contains: []string{"ERROR", testMsg},
+			excludes: []string{"hidden", "goroutine"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := &bytes.Buffer{}
+			tt.write(Development(append([]Option{WithOutput(out)}, tt.opts...)...))
+			got := out.String()
+			for _, want := range tt.contains {
+				if !strings.Contains(got, want) {
+					t.Errorf("Development() wrote %q, want %q", got, want)
+				}
+			}
+			for _, unwanted := range tt.excludes {
+				if strings.Contains(got, unwanted) {
+					t.Errorf("Development() wrote %q, unwanted %q", got, unwanted)
+				}
+			}
+		})
+	}
+}
+
+func TestProduction(t *testing.T) {
+	out := &bytes.Buffer{}
+	w := Production(WithOutput(out), WithSync(true), WithSampling(&Sampling{Tick: time.Hour, Initial: 1}))
+	if w.Enabled(Debug) || !w.Enabled(Info) || !w.Enabled(Err) {
+		t.Errorf("Production() configured Debug or missed Info and Error")
+	}
+	w.Info(testMsg).Info(testMsg).Warning(testMsg).Error(errors.New(testMsg))
+	lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+	want := []string{`"level":"info"`, `"level":"warning"`, `"level":"error"`}
+	if len(lines) != len(want) {
+		t.Fatalf("Production() wrote %q, want %d lines", out.String(), len(want))
+	}
+	for i, line := range lines {
+		if !strings.HasPrefix(line, "{") || !strings.Contains(line, want[i]) || !strings.Contains(line, `"msg":"`+testMsg+`"`) {
+			t.Errorf("Production() line %q, want %s", line, want[i])
+		}
+		if hasStack := strings.Contains(line, `"stack":"goroutine`); hasStack != (i == 2) {
+			t.Errorf("Production() line %q has stack %v", line, hasStack)
+		}
+	}
+}
+
+func TestProduction_belowLevel(t *testing.T) {
+	diagnostics := &bytes.Buffer{}
+	internalLog.SetOutput(diagnostics)
+	defer internalLog.SetOutput(os.Stderr)
+	out := &bytes.Buffer{}
+	w := Production(WithOutput(out), WithSync(true))
+	w.Debug(testMsg).Dispatch(&entry.Entry{Level: Debug, Message: testMsg})
+	if out.Len() != 0 || diagnostics.Len() != 0 {
+		t.Errorf("Production() Debug wrote %q, diagnostics %q", out.String(), diagnostics.String())
+	}
+	w.Dispatch(&entry.Entry{Level: 42, Message: testMsg})
+	if !strings.Contains(diagnostics.String(), "not configured") {
+		t.Errorf("Production() didn't report the unknown logger type")
+	}
+}
+
+func TestProduction_stackTrace(t *testing.T) {
+	out := &bytes.Buffer{}
+	w := Production(WithOutput(out), WithSync(true), WithSampling(&Sampling{Tick: time.Hour, Initial: 1}))
+	w.Dispatch(&entry.Entry{Level: Err, Message: testMsg})
+	if !strings.Contains(out.String(), `"stack":"goroutine`) {
+		t.Errorf("Production() Dispatch wrote %q, want the stack trace", out.String())
+	}
+}
+
+func TestProduction_sampledStack(t *testing.T) {
+	out := &bytes.Buffer{}
+	w := Production(WithOutput(out), WithSync(true), WithSampling(&Sampling{Tick: time.Hour, Initial: 1}))
+	err := errors.New(testMsg)
+	w.ErrorDebug(err)
+	if !strings.Contains(out.String(), `"stack":"goroutine`) {
+		t.Fatalf("Production() ErrorDebug wrote %q, want the stack trace", out.String())
+	}
+	// dropped entries don't capture the stack, so they cost the same with and without it
+	withStack := testing.AllocsPerRun(100, func() { w.ErrorDebug(err) })
+	withoutStack := testing.AllocsPerRun(100, func() { w.Error(err) })
+	if withStack > withoutStack {
+		t.Errorf("Production() sampled ErrorDebug allocates %v times, Error %v", withStack, withoutStack)
+	}
+}
+
+func TestProduction_defaults(t *testing.T) {
+	w, ok := Production().(*Log)
+	if !ok {
+		t.Fatalf("Production() = %T, want *Log", w)
+	}
+	if got := w.config.Loggers[Info]; got.mu != nil || cap(got.Channel) != defaultChanBuffer {
+		t.Errorf("Production() Info logger is not buffered")
+	}
+	if !reflect.DeepEqual(w.config.StackTrace, []uint{Err}) || w.sampler == nil {
+		t.Errorf("Production() stack trace %v, sampler %v", w.config.StackTrace, w.sampler)
+	}
+}
+
+func TestWithStrategies(t *testing.T) {
+	out := &bytes.Buffer{}
+	w := Development(WithStrategies(out), WithTimeFormat(time.Kitchen), WithSync(false), WithChanBuffer(1))
+	l := w.(*Log)
+	if got := l.config.Loggers[Debug]; cap(got.Channel) != 1 || len(got.Strategies) != 1 || got.Strategies[0].(*lockedWriter).w != out {
+		t.Errorf("Development() Debug logger = %v", got)
+	}
+	if l.config.TimeFormat != time.Kitchen {
+		t.Errorf("Development() time format = %v, want %v", l.config.TimeFormat, time.Kitchen)
+	}
+}
+
+func TestWithStrategies_shared(t *testing.T) {
+	tests := []struct {
+		name   string
+		preset func(opts ...Option) Writer
+	}{
+		{
+			preset: Development,
+		},
+		{
+			preset: Production,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := &bytes.Buffer{}
+			w := tt.preset(WithStrategies(out), WithLevel(Info), WithSampling(nil), WithStackTrace())
+			const count = 50
+			var wg sync.WaitGroup
+			for _, write := range []func(){
+				func() { w.Info(testMsg) },
+				func() { w.Warning(testMsg) },
+				func() { w.Error(errors.New(testMsg)) },
+			} {
+				wg.Add(1)
+				go func(write func()) {
+					defer wg.Done()
+					for i := 0; i < count; i++ {
+						write()
+					}
+				}(write)
+			}
+			wg.Wait()
+			shared := w.(*Log).config.Loggers[Info].Strategies[0].(*lockedWriter)
+			for deadline := time.Now().Add(5 * time.Second); ; time.Sleep(time.Millisecond) {
+				shared.mu.Lock()
+				got := strings.Count(out.String(), testMsg+"\n")
+				shared.mu.Unlock()
+				if got == 3*count {
+					break
+				}
+				if time.Now().After(deadline) {
+					t.Fatalf("preset wrote %d lines, want %d", got, 3*count)
+				}
+			}
+		})
+	}
+}
+
+func TestNewDevelopment(t *testing.T) {
+	tests := []struct {
+		name   string
+		create func(opts ...Option) (Writer, error)
+		preset func(opts ...Option) Writer
+	}{
+		{
+			create: NewDevelopment,
+			preset: Development,
+		},
+		{
+			create: NewProduction,
+			preset: Production,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if w, err := tt.create(WithLevel(42)); w != nil || !errors.Is(err, errUnknownLoggerType) {
+				t.Errorf("create() = %v, error = %v", w, err)
+			}
+			if w, err := tt.create(WithOutput(io.Discard)); w == nil || err != nil {
+				t.Errorf("create() = %v, error = %v", w, err)
+			}
+			defer func() {
+				if r := recover(); r == nil {
+					t.Errorf("preset didn't panic")
+				}
+			}()
+			tt.preset(WithLevel(42))
+		})
+	}
+}
+
+func Test_levelsFrom(t *testing.T) {
+	tests := []struct {
+		name       string
+		loggerType uint
+		want       []uint
+	}{
+		{
+			loggerType: Debug,
+			want:       []uint{Debug, Info, Wrn, Err},
+		},
+		{
+			loggerType: Wrn,
+			want:       []uint{Wrn, Err},
+		},
+		{
+			loggerType: 42,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := levelsFrom(tt.loggerType); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("levelsFrom() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,62 @@
+////////////////////////////////////////////////////////////////////////////////
+// Author:   Nikita Koryabkin
+// Email:    Nikita@Koryabk.in
+// Telegram: https://t.me/Apologiz
+////////////////////////////////////////////////////////////////////////////////
+
+package alog
+
+import (
+	"sync"
+	"time"
+
+	"github.com/mylockerteam/alog/entry"
+)
+
+// Sampling limits repeated entries. Within every tick the first Initial entries
+// with the same level and message are written, then every Thereafter-th one
+type Sampling struct {
+	Tick       time.Duration
+	Initial    int
+	Thereafter int
+}
+
+type samplingKey struct {
+	level   uint
+	message string
+}
+
+// sampler counts entries of the current tick
+type sampler struct {
+	*Sampling
+	mu     sync.Mutex
+	start  time.Time
+	counts map[samplingKey]int
+}
+
+func newSampler(sampling *Sampling) *sampler {
+	if sampling == nil {
+		return nil
+	}
+	return &sampler{Sampling: sampling, counts: map[samplingKey]int{}}
+}
+
+// allow reports whether the entry is written, a nil sampler allows everything
+func (s *sampler) allow(e *entry.Entry) bool {
+	if s == nil {
+		return true
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if e.Time.Sub(s.start) >= s.Tick || e.Time.Before(s.start) {
+		s.start = e.Time
+		s.counts = map[samplingKey]int{}
+	}
+	key := samplingKey{level: e.Level, message: e.Message}
+	s.counts[key]++
+	n := s.counts[key]
+	if n <= s.Initial {
+		return true
+	}
+	return s.Thereafter > 0 && (n-s.Initial)%s.Thereafter == 0
+}
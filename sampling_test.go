@@ -0,0 +1,68 @@
+////////////////////////////////////////////////////////////////////////////////
+// Author:   Nikita Koryabkin
+// Email:    Nikita@Koryabk.in
+// Telegram: https://t.me/Apologiz
+////////////////////////////////////////////////////////////////////////////////
+
+package alog
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/mylockerteam/alog/entry"
+)
+
+func TestSampler_allow(t *testing.T) {
+	start := time.Date(2026, time.October, 19, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		sampling *Sampling
+		entries  []*entry.Entry
+		want     []bool
+	}{
+		{
+			entries: []*entry.Entry{
+				{Time: start, Message: testMsg},
+				{Time: start, Message: testMsg},
+			},
+			want: []bool{true, true},
+		},
+		{
+			sampling: &Sampling{Tick: time.Second, Initial: 2, Thereafter: 3},
+			entries: []*entry.Entry{
+				{Time: start, Message: testMsg},
+				{Time: start, Message: testMsg},
+				{Time: start, Message: testMsg},
+				{Time: start, Message: testMsg},
+				{Time: start, Message: testMsg},
+				{Time: start, Message: "other"},
+				{Time: start, Level: Err, Message: testMsg},
+				{Time: start.Add(time.Second), Message: testMsg},
+			},
+			want: []bool{true, true, false, false, true, true, true, true},
+		},
+		{
+			sampling: &Sampling{Tick: time.Second, Initial: 1},
+			entries: []*entry.Entry{
+				{Time: start, Message: testMsg},
+				{Time: start.Add(time.Millisecond), Message: testMsg},
+				{Time: start.Add(time.Second), Message: testMsg},
+			},
+			want: []bool{true, false, true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newSampler(tt.sampling)
+			var got []bool
+			for _, e := range tt.entries {
+				got = append(got, s.allow(e))
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("sampler.allow() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,110 @@
+////////////////////////////////////////////////////////////////////////////////
+// Author:   Nikita Koryabkin
+// Email:    Nikita@Koryabk.in
+// Telegram: https://t.me/Apologiz
+////////////////////////////////////////////////////////////////////////////////
+
+package alog
+
+import (
+	"context"
+	"log/slog"
+	"runtime"
+
+	"github.com/mylockerteam/alog/entry"
+)
+
+// SlogHandler slog.Handler which writes records to the alog loggers
+type SlogHandler struct {
+	writer Writer
+	fields []entry.Field
+	group  string
+}
+
+// NewSlogHandler creates slog.Handler on top of the writer
+func NewSlogHandler(writer Writer) *SlogHandler {
+	return &SlogHandler{writer: writer}
+}
+
+// SlogLevel returns the logger type for the slog level.
+// Levels below Info go to Debug, Info to Info, Warn to Warning, Error and above to Error
+func SlogLevel(level slog.Level) uint {
+	switch {
+	case level >= slog.LevelError:
+		return Err
+	case level >= slog.LevelWarn:
+		return Wrn
+	case level >= slog.LevelInfo:
+		return Info
+	default:
+		return Debug
+	}
+}
+
+// Enabled reports whether the logger for the level is configured
+func (h *SlogHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return h.writer.Enabled(SlogLevel(level))
+}
+
+// Handle passes the record with its attributes to the logger
+func (h *SlogHandler) Handle(_ context.Context, r slog.Record) error {
+	e := &entry.Entry{
+		Level:   SlogLevel(r.Level),
+		Time:    r.Time,
+		Message: r.Message,
+		Fields:  make([]entry.Field, len(h.fields), len(h.fields)+r.NumAttrs()),
+	}
+	copy(e.Fields, h.fields)
+	r.Attrs(func(attr slog.Attr) bool {
+		e.Fields = appendSlogAttr(e.Fields, h.group, attr)
+		return true
+	})
+	if r.PC != 0 {
+		frame, _ := runtime.CallersFrames([]uintptr{r.PC}).Next()
+		e.Caller = entry.Caller{File: frame.File, Line: frame.Line, Function: frame.Function}
+	}
+	h.writer.Dispatch(e)
+	return nil
+}
+
+// WithAttrs returns a handler which adds the attributes to every record
+func (h *SlogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	clone := *h
+	clone.fields = make([]entry.Field, len(h.fields), len(h.fields)+len(attrs))
+	copy(clone.fields, h.fields)
+	for _, attr := range attrs {
+		clone.fields = appendSlogAttr(clone.fields, h.group, attr)
+	}
+	return &clone
+}
+
+// WithGroup returns a handler which qualifies the keys of further attributes with the group name
+func (h *SlogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	clone := *h
+	clone.group = h.group + name + "."
+	return &clone
+}
+
+// appendSlogAttr adds the attribute to the fields. Groups are flattened into keys separated by dots
+func appendSlogAttr(fields []entry.Field, group string, attr slog.Attr) []entry.Field {
+	attr.Value = attr.Value.Resolve()
+	if attr.Equal(slog.Attr{}) {
+		return fields
+	}
+	if attr.Value.Kind() != slog.KindGroup {
+		return append(fields, entry.Field{Key: group + attr.Key, Value: attr.Value.Any()})
+	}
+	if attr.Key != "" {
+		group += attr.Key + "."
+	}
+	for _, a := range attr.Value.Group() {
+		fields = appendSlogAttr(fields, group, a)
+	}
+	return fields
+}
@@ -0,0 +1,139 @@
+////////////////////////////////////////////////////////////////////////////////
+// Author:   Nikita Koryabkin
+// Email:    Nikita@Koryabk.in
+// Telegram: https://t.me/Apologiz
+////////////////////////////////////////////////////////////////////////////////
+
+package alog
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestSlogLevel(t *testing.T) {
+	tests := []struct {
+		name  string
+		level slog.Level
+		want  uint
+	}{
+		{
+			level: slog.LevelDebug,
+			want:  Debug,
+		},
+		{
+			level: slog.LevelInfo - 1,
+			want:  Debug,
+		},
+		{
+			level: slog.LevelInfo,
+			want:  Info,
+		},
+		{
+			level: slog.LevelWarn,
+			want:  Wrn,
+		},
+		{
+			level: slog.LevelError,
+			want:  Err,
+		},
+		{
+			level: slog.LevelError + 4,
+			want:  Err,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SlogLevel(tt.level); got != tt.want {
+				t.Errorf("SlogLevel() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSlogHandler_Enabled(t *testing.T) {
+	h := NewSlogHandler(&Log{config: configProvider()})
+	tests := []struct {
+		name  string
+		level slog.Level
+		want  bool
+	}{
+		{
+			level: slog.LevelInfo,
+			want:  true,
+		},
+		{
+			level: slog.LevelError,
+			want:  false,
+		},
+		{
+			level: slog.LevelDebug,
+			want:  false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := h.Enabled(context.Background(), tt.level); got != tt.want {
+				t.Errorf("SlogHandler.Enabled() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSlogHandler_Handle(t *testing.T) {
+	tests := []struct {
+		name string
+		log  func(logger *slog.Logger)
+		want string
+	}{
+		{
+			log: func(logger *slog.Logger) {
+				logger.Info(testMsg)
+			},
+			want: testMsg,
+		},
+		{
+			log: func(logger *slog.Logger) {
+				logger.Info(testMsg, "a", 1, slog.Group("g", "b", "two words"))
+			},
+			want: testMsg + ` a=1 g.b="two words"`,
+		},
+		{
+			log: func(logger *slog.Logger) {
+				logger.With("a", 1).WithGroup("g").With("b", 2).WithGroup("").Info(testMsg, "c", 3, slog.Group("empty"))
+			},
+			want: testMsg + " a=1 g.b=2 g.c=3",
+		},
+		{
+			log: func(logger *slog.Logger) {
+				logger.WithGroup("g").Info(testMsg, slog.Group("", "a", 1), slog.Attr{})
+			},
+			want: testMsg + " g.a=1",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := configProvider()
+			_, fileName, _, _ := runtime.Caller(0)
+			tt.log(slog.New(NewSlogHandler(&Log{config: config}).WithAttrs(nil)))
+			got := <-config.Loggers[Info].Channel
+			if !strings.HasPrefix(got, fmt.Sprintf("[%s] ", Name(Info))) || !strings.Contains(got, fileName) || !strings.HasSuffix(got, ";"+tt.want+"\n") {
+				t.Errorf("SlogHandler.Handle() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSlogHandler_debug(t *testing.T) {
+	out := &bytes.Buffer{}
+	logger := slog.New(NewSlogHandler(Development(WithOutput(out))))
+	logger.Debug(testMsg)
+	if got := out.String(); !strings.Contains(got, "DEBUG") || !strings.Contains(got, testMsg) {
+		t.Errorf("SlogHandler.Handle() wrote %q, want the Debug message", got)
+	}
+}
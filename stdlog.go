@@ -0,0 +1,79 @@
+////////////////////////////////////////////////////////////////////////////////
+// Author:   Nikita Koryabkin
+// Email:    Nikita@Koryabk.in
+// Telegram: https://t.me/Apologiz
+////////////////////////////////////////////////////////////////////////////////
+
+package alog
+
+import (
+	"fmt"
+	"log"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/mylockerteam/alog/entry"
+)
+
+const stdLogPackage = "log"
+
+// stdLogWriter receives the output of the standard logger
+type stdLogWriter struct {
+	log        *Log
+	loggerType uint
+}
+
+// RedirectStdLog sends the output of the standard log package to the logger of the given type.
+// It returns a function that restores the previous output, flags and prefix of the standard logger.
+func (a *Log) RedirectStdLog(loggerType uint) (func(), error) {
+	if a.config.Loggers[loggerType] == nil {
+		return nil, fmt.Errorf("logger %d not configured", loggerType)
+	}
+	flags, prefix, output := log.Flags(), log.Prefix(), log.Writer()
+	log.SetFlags(0)
+	log.SetPrefix("")
+	log.SetOutput(&stdLogWriter{log: a, loggerType: loggerType})
+	return func() {
+		log.SetFlags(flags)
+		log.SetPrefix(prefix)
+		log.SetOutput(output)
+	}, nil
+}
+
+func (w *stdLogWriter) Write(p []byte) (n int, err error) {
+	w.log.Dispatch(&entry.Entry{
+		Level:   w.loggerType,
+		Time:    time.Now(),
+		Caller:  stdLogCaller(),
+		Message: strings.TrimSuffix(string(p), "\n"),
+	})
+	return len(p), nil
+}
+
+// stdLogCaller returns the position of the code that called the standard log package
+func stdLogCaller() entry.Caller {
+	pc := make([]uintptr, 32)
+	frames := runtime.CallersFrames(pc[:runtime.Callers(2, pc)])
+	inLogPackage := false
+	for {
+		frame, more := frames.Next()
+		if funcPackage(frame.Function) == stdLogPackage {
+			inLogPackage = true
+		} else if inLogPackage {
+			return entry.Caller{File: frame.File, Line: frame.Line, Function: frame.Function}
+		}
+		if !more {
+			return entry.Caller{}
+		}
+	}
+}
+
+// funcPackage returns the import path of the package from a fully qualified function name
+func funcPackage(function string) string {
+	slash := strings.LastIndex(function, "/")
+	if dot := strings.Index(function[slash+1:], "."); dot >= 0 {
+		return function[:slash+1+dot]
+	}
+	return function
+}
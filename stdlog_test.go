@@ -0,0 +1,91 @@
+////////////////////////////////////////////////////////////////////////////////
+// Author:   Nikita Koryabkin
+// Email:    Nikita@Koryabk.in
+// Telegram: https://t.me/Apologiz
+////////////////////////////////////////////////////////////////////////////////
+
+package alog
+
+import (
+	"fmt"
+	"log"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestLog_RedirectStdLog(t *testing.T) {
+	tests := []struct {
+		name       string
+		config     *Config
+		loggerType uint
+		wantErr    bool
+	}{
+		{
+			config:     configProvider(),
+			loggerType: Info,
+			wantErr:    false,
+		},
+		{
+			config:     configProvider(),
+			loggerType: Err,
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output := log.Writer()
+			restore, err := (&Log{config: tt.config}).RedirectStdLog(tt.loggerType)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Log.RedirectStdLog() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				return
+			}
+			_, fileName, fileLine, _ := runtime.Caller(0)
+			log.Print(testMsg)
+			restore()
+			want := fmt.Sprintf("[%s] ", Name(tt.loggerType))
+			got := <-tt.config.Loggers[tt.loggerType].Channel
+			if !strings.HasPrefix(got, want) || !strings.HasSuffix(got, fmt.Sprintf("%s:%d;%s\n", fileName, fileLine+1, testMsg)) {
+				t.Errorf("Log.RedirectStdLog() = %v, want %v...%s:%d;%s", got, want, fileName, fileLine+1, testMsg)
+			}
+			if log.Writer() != output {
+				t.Errorf("Log.RedirectStdLog() output was not restored")
+			}
+		})
+	}
+}
+
+func Test_funcPackage(t *testing.T) {
+	tests := []struct {
+		name     string
+		function string
+		want     string
+	}{
+		{
+			function: "log.Printf",
+			want:     "log",
+		},
+		{
+			function: "log.(*Logger).output",
+			want:     "log",
+		},
+		{
+			function: "github.com/mylockerteam/alog.(*stdLogWriter).Write",
+			want:     "github.com/mylockerteam/alog",
+		},
+		{
+			function: "",
+			want:     "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := funcPackage(tt.function); got != tt.want {
+				t.Errorf("funcPackage() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
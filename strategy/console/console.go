@@ -0,0 +1,199 @@
+////////////////////////////////////////////////////////////////////////////////
+// Author:   Nikita Koryabkin
+// Email:    Nikita@Koryabk.in
+// Telegram: https://t.me/Apologiz
+////////////////////////////////////////////////////////////////////////////////
+
+package console
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/mylockerteam/alog/entry"
+)
+
+const (
+	defaultTimeFormat = "15:04:05.000"
+	levelWidth        = 7
+	stackIndent       = "    "
+)
+
+// ANSI escape sequences
+const (
+	reset  = "\x1b[0m"
+	red    = "\x1b[31m"
+	green  = "\x1b[32m"
+	yellow = "\x1b[33m"
+	blue   = "\x1b[34m"
+	cyan   = "\x1b[36m"
+	faint  = "\x1b[2m"
+)
+
+// levelColors colors of the logger names
+var levelColors = map[string]string{
+	"Debug":   blue,
+	"Info":    green,
+	"Warning": yellow,
+	"Error":   red,
+}
+
+// Strategy human-friendly logging strategy in the console.
+// Entries are written in aligned columns: time, level, caller, message and fields,
+// the stack trace follows indented
+type Strategy struct {
+	_          io.Writer
+	out        io.Writer
+	color      *bool
+	timeFormat string
+	fullCaller bool
+
+	mu          sync.Mutex
+	callerWidth int
+}
+
+// Option configures the console strategy
+type Option func(s *Strategy)
+
+// Get console write strategy, writes to stderr by default.
+// Colors are used when the output is a terminal and NO_COLOR is not set
+func Get(options ...Option) io.Writer {
+	s := &Strategy{out: os.Stderr, timeFormat: defaultTimeFormat}
+	for _, option := range options {
+		option(s)
+	}
+	if s.color == nil {
+		color := colorSupported(s.out)
+		s.color = &color
+	}
+	return s
+}
+
+// WithWriter sets the output
+func WithWriter(out io.Writer) Option {
+	return func(s *Strategy) {
+		s.out = out
+	}
+}
+
+// WithColor enables or disables colors regardless of the output
+func WithColor(enabled bool) Option {
+	return func(s *Strategy) {
+		s.color = &enabled
+	}
+}
+
+// WithTimeFormat sets the layout of the time column, 15:04:05.000 by default
+func WithTimeFormat(layout string) Option {
+	return func(s *Strategy) {
+		s.timeFormat = layout
+	}
+}
+
+// WithFullCaller writes the full path of the caller's file instead of the directory and the file name
+func WithFullCaller() Option {
+	return func(s *Strategy) {
+		s.fullCaller = true
+	}
+}
+
+// Write writes the formatted line as is with a single trailing newline, the level prefix is colored
+func (s *Strategy) Write(p []byte) (n int, err error) {
+	line := strings.TrimRight(string(p), "\n")
+	if *s.color && strings.HasPrefix(line, "[") {
+		if end := strings.IndexByte(line, ']'); end > 0 {
+			if color, ok := levelColors[line[1:end]]; ok {
+				line = color + line[:end+1] + reset + line[end+1:]
+			}
+		}
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, err := io.WriteString(s.out, line+"\n"); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// WriteEntry writes the entry in columns
+func (s *Strategy) WriteEntry(e *entry.Entry, p []byte) (n int, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, err := s.out.Write(s.format(e)); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+func (s *Strategy) format(e *entry.Entry) []byte {
+	var b bytes.Buffer
+	if !e.Time.IsZero() {
+		s.paint(&b, faint, e.Time.Format(s.timeFormat))
+		b.WriteByte(' ')
+	}
+	level := fmt.Sprintf("%-*s", levelWidth, strings.ToUpper(e.LevelName))
+	s.paint(&b, levelColors[e.LevelName], level)
+	if e.Caller.Defined() {
+		c := s.caller(e.Caller)
+		if len(c) > s.callerWidth {
+			s.callerWidth = len(c)
+		}
+		b.WriteByte(' ')
+		s.paint(&b, faint, fmt.Sprintf("%-*s", s.callerWidth, c))
+	}
+	b.WriteString("  ")
+	b.WriteString(e.Message)
+	for _, field := range e.Fields {
+		b.WriteString("  ")
+		s.paint(&b, cyan, field.Key)
+		b.WriteByte('=')
+		formatted := entry.FormatFields([]entry.Field{field})
+		b.WriteString(formatted[len(field.Key)+1:])
+	}
+	b.WriteByte('\n')
+	if e.Stack != "" {
+		for _, line := range strings.Split(strings.TrimRight(e.Stack, "\n"), "\n") {
+			s.paint(&b, faint, stackIndent+line)
+			b.WriteByte('\n')
+		}
+	}
+	return b.Bytes()
+}
+
+// caller returns the caller shortened to the directory and the file name
+func (s *Strategy) caller(c entry.Caller) string {
+	if s.fullCaller {
+		return c.String()
+	}
+	dir, file := filepath.Split(c.File)
+	return fmt.Sprintf("%s:%d", filepath.Join(filepath.Base(dir), file), c.Line)
+}
+
+func (s *Strategy) paint(b *bytes.Buffer, color string, text string) {
+	if !*s.color || color == "" {
+		b.WriteString(text)
+		return
+	}
+	b.WriteString(color)
+	b.WriteString(text)
+	b.WriteString(reset)
+}
+
+// colorSupported reports whether the output is a terminal and colors are not disabled
+// by NO_COLOR or TERM=dumb, see https://no-color.org
+func colorSupported(out io.Writer) bool {
+	if os.Getenv("NO_COLOR") != "" || os.Getenv("TERM") == "dumb" {
+		return false
+	}
+	f, ok := out.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,145 @@
+////////////////////////////////////////////////////////////////////////////////
+// Author:   Nikita Koryabkin
+// Email:    Nikita@Koryabk.in
+// Telegram: https://t.me/Apologiz
+////////////////////////////////////////////////////////////////////////////////
+
+package console
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/mylockerteam/alog/entry"
+)
+
+func TestStrategy_WriteEntry(t *testing.T) {
+	e := &entry.Entry{
+		LevelName: "Error",
+		Time:      time.Date(2026, time.October, 19, 12, 30, 0, 0, time.UTC),
+		Caller:    entry.Caller{File: "/src/app/main.go", Line: 7},
+		Message:   "refused",
+		Fields:    []entry.Field{{Key: "tenant", Value: "acme"}, {Key: "reason", Value: "no route"}},
+		Stack:     "goroutine 1 [running]:\nmain.main()\n",
+	}
+	tests := []struct {
+		name    string
+		options []Option
+		want    string
+	}{
+		{
+			options: []Option{WithColor(false)},
+			want: "12:30:00.000 ERROR   app/main.go:7  refused  tenant=acme  reason=\"no route\"\n" +
+				"    goroutine 1 [running]:\n    main.main()\n",
+		},
+		{
+			options: []Option{WithColor(false), WithFullCaller(), WithTimeFormat(time.RFC3339)},
+			want: "2026-10-19T12:30:00Z ERROR   /src/app/main.go:7  refused  tenant=acme  reason=\"no route\"\n" +
+				"    goroutine 1 [running]:\n    main.main()\n",
+		},
+		{
+			options: []Option{WithColor(true)},
+			want: faint + "12:30:00.000" + reset + " " + red + "ERROR  " + reset + " " + faint + "app/main.go:7" + reset +
+				"  refused  " + cyan + "tenant" + reset + "=acme  " + cyan + "reason" + reset + "=\"no route\"\n" +
+				faint + "    goroutine 1 [running]:" + reset + "\n" + faint + "    main.main()" + reset + "\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := &bytes.Buffer{}
+			s := Get(append(tt.options, WithWriter(out))...).(*Strategy)
+			if n, err := s.WriteEntry(e, []byte("line")); err != nil || n != 4 {
+				t.Fatalf("WriteEntry() = %d, error = %v", n, err)
+			}
+			if got := out.String(); got != tt.want {
+				t.Errorf("WriteEntry() wrote\n%q, want\n%q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStrategy_WriteEntry_align(t *testing.T) {
+	out := &bytes.Buffer{}
+	s := Get(WithWriter(out), WithColor(false)).(*Strategy)
+	_, _ = s.WriteEntry(&entry.Entry{LevelName: "Warning", Caller: entry.Caller{File: "/app/server.go", Line: 120}, Message: "a"}, nil)
+	_, _ = s.WriteEntry(&entry.Entry{LevelName: "Info", Caller: entry.Caller{File: "/app/main.go", Line: 7}, Message: "b"}, nil)
+	want := "WARNING app/server.go:120  a\nINFO    app/main.go:7      b\n"
+	if got := out.String(); got != want {
+		t.Errorf("WriteEntry() wrote\n%q, want\n%q", got, want)
+	}
+}
+
+func TestStrategy_Write(t *testing.T) {
+	tests := []struct {
+		name  string
+		color bool
+		p     string
+		want  string
+	}{
+		{
+			p:    "[Info] 2026-10-19;message\n\n",
+			want: "[Info] 2026-10-19;message\n",
+		},
+		{
+			color: true,
+			p:     "[Error] 2026-10-19;message\n",
+			want:  red + "[Error]" + reset + " 2026-10-19;message\n",
+		},
+		{
+			color: true,
+			p:     "[unknown] message",
+			want:  "[unknown] message\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := &bytes.Buffer{}
+			s := Get(WithWriter(out), WithColor(tt.color))
+			if n, err := s.Write([]byte(tt.p)); err != nil || n != len(tt.p) {
+				t.Fatalf("Write() = %d, error = %v", n, err)
+			}
+			if got := out.String(); got != tt.want {
+				t.Errorf("Write() wrote %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_colorSupported(t *testing.T) {
+	file, err := os.CreateTemp(t.TempDir(), "console")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	tests := []struct {
+		name    string
+		out     io.Writer
+		noColor string
+		want    bool
+	}{
+		{
+			out:  &bytes.Buffer{},
+			want: false,
+		},
+		{
+			out:  file,
+			want: false,
+		},
+		{
+			out:     os.Stdout,
+			noColor: "1",
+			want:    false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("NO_COLOR", tt.noColor)
+			if got := colorSupported(tt.out); got != tt.want {
+				t.Errorf("colorSupported() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
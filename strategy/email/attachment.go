@@ -0,0 +1,142 @@
+////////////////////////////////////////////////////////////////////////////////
+// Author:   Nikita Koryabkin
+// Email:    Nikita@Koryabk.in
+// Telegram: https://t.me/Apologiz
+////////////////////////////////////////////////////////////////////////////////
+
+package email
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/mylockerteam/alog/entry"
+	"gopkg.in/gomail.v2"
+)
+
+const (
+	stackAttachment  = "stack.txt"
+	fieldsAttachment = "fields.json"
+	recentAttachment = "recent.log"
+)
+
+// attachment file attached to the email
+type attachment struct {
+	name string
+	data []byte
+}
+
+// Recent keeps the last messages written to it, add it to the strategies of the loggers
+// whose messages should be attached, see WithAttachRecent
+type Recent struct {
+	_     io.Writer
+	mu    sync.Mutex
+	lines []string
+	next  int
+}
+
+// NewRecent returns the buffer of the last size messages
+func NewRecent(size int) *Recent {
+	return &Recent{lines: make([]string, 0, size)}
+}
+
+func (r *Recent) Write(p []byte) (n int, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if cap(r.lines) == 0 {
+		return len(p), nil
+	}
+	line := strings.TrimSuffix(string(p), "\n")
+	if len(r.lines) < cap(r.lines) {
+		r.lines = append(r.lines, line)
+	} else {
+		r.lines[r.next] = line
+		r.next = (r.next + 1) % len(r.lines)
+	}
+	return len(p), nil
+}
+
+// Lines returns the kept messages from the oldest
+func (r *Recent) Lines() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append(append([]string(nil), r.lines[r.next:]...), r.lines[:r.next]...)
+}
+
+// WithAttachStack attaches the stack trace of the entry as stack.txt instead of pasting it into Data
+func WithAttachStack() Option {
+	return func(s *Strategy) {
+		s.attachStack = true
+	}
+}
+
+// WithAttachFields attaches the fields of the entry as fields.json
+func WithAttachFields() Option {
+	return func(s *Strategy) {
+		s.attachFields = true
+	}
+}
+
+// WithAttachRecent attaches the messages kept by the buffer as recent.log
+func WithAttachRecent(recent *Recent) Option {
+	return func(s *Strategy) {
+		s.recent = recent
+	}
+}
+
+// attachments returns the files of the entry, the first one of a digest
+func (s *Strategy) attachments(e *entry.Entry) []attachment {
+	var files []attachment
+	if e != nil && s.attachStack && e.Stack != "" {
+		files = append(files, attachment{name: stackAttachment, data: []byte(e.Stack)})
+	}
+	if e != nil && s.attachFields && len(e.Fields) > 0 {
+		files = append(files, attachment{name: fieldsAttachment, data: fieldsJSON(e.Fields)})
+	}
+	if s.recent != nil {
+		if lines := s.recent.Lines(); len(lines) > 0 {
+			files = append(files, attachment{name: recentAttachment, data: []byte(strings.Join(lines, "\n") + "\n")})
+		}
+	}
+	return files
+}
+
+// withoutStack cuts the attached stack trace with its --- delimiter from the text,
+// the following messages of a digest are kept
+func (s *Strategy) withoutStack(e *entry.Entry, data string) string {
+	if e == nil || !s.attachStack || e.Stack == "" {
+		return data
+	}
+	i := strings.Index(data, e.Stack)
+	if i < 0 {
+		return data
+	}
+	rest := strings.TrimPrefix(data[i+len(e.Stack):], "\n---")
+	return strings.TrimRight(data[:i], "\n") + "\n" + strings.TrimLeft(rest, "\n")
+}
+
+// fieldsJSON returns the fields as a JSON object, values which can't be encoded are formatted
+func fieldsJSON(fields []entry.Field) []byte {
+	object := make(map[string]interface{}, len(fields))
+	for _, field := range fields {
+		object[field.Key] = field.Value
+		if _, err := json.Marshal(field.Value); err != nil {
+			object[field.Key] = fmt.Sprint(field.Value)
+		}
+	}
+	data, _ := json.MarshalIndent(object, "", "  ")
+	return data
+}
+
+func attach(msg *gomail.Message, files []attachment) {
+	for _, file := range files {
+		data := file.data
+		msg.Attach(file.name, gomail.SetCopyFunc(func(w io.Writer) error {
+			_, err := w.Write(data)
+			return err
+		}))
+	}
+}
@@ -0,0 +1,107 @@
+////////////////////////////////////////////////////////////////////////////////
+// Author:   Nikita Koryabkin
+// Email:    Nikita@Koryabk.in
+// Telegram: https://t.me/Apologiz
+////////////////////////////////////////////////////////////////////////////////
+
+package email
+
+import (
+	"bytes"
+	"encoding/base64"
+	"html/template"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/mylockerteam/alog/entry"
+	"github.com/mylockerteam/mailSender"
+	"gopkg.in/gomail.v2"
+)
+
+func TestRecent(t *testing.T) {
+	tests := []struct {
+		name   string
+		size   int
+		writes []string
+		want   []string
+	}{
+		{
+			size:   3,
+			writes: []string{"a\n", "b\n"},
+			want:   []string{"a", "b"},
+		},
+		{
+			size:   3,
+			writes: []string{"a\n", "b\n", "c\n", "d\n", "e\n"},
+			want:   []string{"c", "d", "e"},
+		},
+		{
+			size:   0,
+			writes: []string{"a\n"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewRecent(tt.size)
+			for _, line := range tt.writes {
+				_, _ = r.Write([]byte(line))
+			}
+			if got := r.Lines(); !reflect.DeepEqual(got, tt.want) && (len(got) != 0 || tt.want != nil) {
+				t.Errorf("Recent.Lines() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithAttachStack(t *testing.T) {
+	recent := NewRecent(2)
+	_, _ = recent.Write([]byte("[Info] started\n"))
+	msg := gomail.NewMessage()
+	msg.SetHeader("From", "no-reply@example.com")
+	msg.SetHeader("To", "test@example.com")
+	sender := &chanSender{messages: make(chan mailSender.Message, 1)}
+	tpl, _ := template.New("test").Parse("<pre>{{ .Data }}</pre>")
+	s := Get(sender, msg, tpl, WithAttachStack(), WithAttachFields(), WithAttachRecent(recent)).(*Strategy)
+	e := &entry.Entry{
+		Message: "failure",
+		Fields:  []entry.Field{{Key: "tenant", Value: "acme"}, {Key: "callback", Value: func() {}}},
+		Stack:   "goroutine 1 [running]:",
+	}
+	_, _ = s.WriteEntry(e, []byte("[Error] failure\ngoroutine 1 [running]:\n---\n\n"))
+	got := <-sender.messages
+	if data := got.Data["Data"]; data != "[Error] failure\n" {
+		t.Errorf("Data = %q, want the message without the stack", data)
+	}
+	buf := &bytes.Buffer{}
+	if _, err := got.Message.WriteTo(buf); err != nil {
+		t.Fatal(err)
+	}
+	attachments := map[string]string{
+		stackAttachment:  "goroutine 1 [running]:",
+		fieldsAttachment: "{\n  \"callback\": \"",
+		recentAttachment: "[Info] started\n",
+	}
+	for name, content := range attachments {
+		if !strings.Contains(buf.String(), name) || !strings.Contains(buf.String(), base64.StdEncoding.EncodeToString([]byte(content))[:16]) {
+			t.Errorf("email has no attachment %s with %q", name, content)
+		}
+	}
+	if string(fieldsJSON(e.Fields[:1])) != "{\n  \"tenant\": \"acme\"\n}" {
+		t.Errorf("fieldsJSON() = %s", fieldsJSON(e.Fields[:1]))
+	}
+}
+
+func TestWithAttachStack_digest(t *testing.T) {
+	sender := &chanSender{messages: make(chan mailSender.Message, 1)}
+	tpl, _ := template.New("test").Parse("{{ .Data }}")
+	s := Get(sender, gomail.NewMessage(), tpl, WithAttachStack(), WithDigest(0, 3)).(*Strategy)
+	_, _ = s.WriteEntry(&entry.Entry{Level: 2, Message: "failure", Stack: "goroutine 1 [running]:"},
+		[]byte("[Error] failure\ngoroutine 1 [running]:\n---\n\n"))
+	_, _ = s.WriteEntry(&entry.Entry{Level: 1, Message: "retrying"}, []byte("[Warning] retrying\n"))
+	_, _ = s.WriteEntry(&entry.Entry{Level: 0, Message: "recovered"}, []byte("[Info] recovered\n"))
+	got := <-sender.messages
+	if want := "[Error] failure\n[Warning] retrying\n[Info] recovered\n"; got.Data["Data"] != want {
+		t.Errorf("Data = %q, want %q", got.Data["Data"], want)
+	}
+}
@@ -0,0 +1,115 @@
+////////////////////////////////////////////////////////////////////////////////
+// Author:   Nikita Koryabkin
+// Email:    Nikita@Koryabk.in
+// Telegram: https://t.me/Apologiz
+////////////////////////////////////////////////////////////////////////////////
+
+package email
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mylockerteam/alog/entry"
+)
+
+// digest entries collected during the window
+type digest struct {
+	window     time.Duration
+	maxEntries int
+
+	mu    sync.Mutex
+	items []*digestItem
+	index map[string]*digestItem
+	total int
+	first *entry.Entry
+	timer *time.Timer
+}
+
+// digestItem the first message of equal entries and their number
+type digestItem struct {
+	message string
+	count   int
+}
+
+// WithDigest collects messages during the window and sends them in one email,
+// also sent when maxEntries messages are collected. Zero maxEntries means no limit.
+// Entries with the same logger type and message are listed once with their count.
+// The template gets the listing as Data, the number of messages as Count
+// and the fields of the first entry, see WithSubject
+func WithDigest(window time.Duration, maxEntries int) Option {
+	return func(s *Strategy) {
+		s.digest = &digest{window: window, maxEntries: maxEntries, index: map[string]*digestItem{}}
+	}
+}
+
+// Flush sends the pending digest
+func (s *Strategy) Flush() {
+	if s.digest == nil {
+		return
+	}
+	if data, count, first := s.digest.take(); count > 0 {
+		s.send(first, data, count)
+	}
+}
+
+// Close sends the pending digest and the number of the suppressed messages, call it on shutdown
+func (s *Strategy) Close() error {
+	s.Flush()
+	s.closeSummary()
+	return nil
+}
+
+// add collects the message of the entry and reports whether the digest is full
+func (d *digest) add(e *entry.Entry, message string, flush func()) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	key := digestKey(e, message)
+	if item, ok := d.index[key]; ok {
+		item.count++
+	} else {
+		item = &digestItem{message: message, count: 1}
+		d.index[key] = item
+		d.items = append(d.items, item)
+	}
+	if d.total++; d.total == 1 {
+		d.first = e
+		if d.window > 0 {
+			d.timer = time.AfterFunc(d.window, flush)
+		}
+	}
+	return d.maxEntries > 0 && d.total >= d.maxEntries
+}
+
+// take returns the listing of the collected messages, their number and the first entry,
+// a new window is started
+func (d *digest) take() (string, int, *entry.Entry) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.timer != nil {
+		d.timer.Stop()
+		d.timer = nil
+	}
+	var b strings.Builder
+	for _, item := range d.items {
+		if item.count > 1 {
+			b.WriteString(fmt.Sprintf("[%d times] ", item.count))
+		}
+		b.WriteString(strings.TrimSuffix(item.message, "\n"))
+		b.WriteByte('\n')
+	}
+	total, first := d.total, d.first
+	d.items, d.index, d.total, d.first = nil, map[string]*digestItem{}, 0, nil
+	return b.String(), total, first
+}
+
+// digestKey groups the entries by the logger type and the message, plain messages by the text
+func digestKey(e *entry.Entry, message string) string {
+	if e == nil {
+		return message
+	}
+	return strconv.FormatUint(uint64(e.Level), 10) + "\x00" + e.Message
+}
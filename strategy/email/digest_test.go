@@ -0,0 +1,79 @@
+////////////////////////////////////////////////////////////////////////////////
+// Author:   Nikita Koryabkin
+// Email:    Nikita@Koryabk.in
+// Telegram: https://t.me/Apologiz
+////////////////////////////////////////////////////////////////////////////////
+
+package email
+
+import (
+	"html/template"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/mylockerteam/alog/entry"
+	"github.com/mylockerteam/mailSender"
+	"gopkg.in/gomail.v2"
+)
+
+// chanSender keeps the sent messages
+type chanSender struct {
+	messages chan mailSender.Message
+}
+
+func (s *chanSender) SendAsync(message mailSender.Message) {
+	s.messages <- message
+}
+
+func TestWithDigest(t *testing.T) {
+	tests := []struct {
+		name       string
+		maxEntries int
+		write      func(s *Strategy)
+		want       mailSender.EmailData
+	}{
+		{
+			maxEntries: 3,
+			write: func(s *Strategy) {
+				_, _ = s.WriteEntry(&entry.Entry{Level: 2, Message: "refused"}, []byte("12:00 refused\n"))
+				_, _ = s.Write([]byte("plain\n"))
+				_, _ = s.WriteEntry(&entry.Entry{Level: 2, Message: "refused"}, []byte("12:01 refused\n"))
+			},
+			want: mailSender.EmailData{"Data": "[2 times] 12:00 refused\nplain\n", "Count": "3"},
+		},
+		{
+			write: func(s *Strategy) {
+				_, _ = s.Write([]byte("first\n"))
+				s.Close()
+			},
+			want: mailSender.EmailData{"Data": "first\n", "Count": "1"},
+		},
+		{
+			write: func(s *Strategy) {
+				_, _ = s.WriteEntry(&entry.Entry{Level: 2, Message: "refused"}, []byte("refused\n"))
+				_, _ = s.WriteEntry(&entry.Entry{Level: 1, Message: "refused"}, []byte("refused\n"))
+			},
+			want: mailSender.EmailData{"Data": "refused\nrefused\n", "Count": "2"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sender := &chanSender{messages: make(chan mailSender.Message, 2)}
+			tpl, _ := template.New("test").Parse("{{ .Data }}")
+			s := Get(sender, gomail.NewMessage(), tpl, WithDigest(10*time.Millisecond, tt.maxEntries)).(*Strategy)
+			tt.write(s)
+			message := <-sender.messages
+			got := mailSender.EmailData{"Data": message.Data["Data"], "Count": message.Data["Count"]}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("digest = %v, want %v", got, tt.want)
+			}
+			s.Flush()
+			select {
+			case message := <-sender.messages:
+				t.Errorf("unexpected digest %v", message.Data)
+			case <-time.After(20 * time.Millisecond):
+			}
+		})
+	}
+}
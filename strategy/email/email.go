@@ -7,10 +7,16 @@
 package email
 
 import (
+	"fmt"
+	"github.com/mylockerteam/alog/entry"
 	"github.com/mylockerteam/mailSender"
 	"gopkg.in/gomail.v2"
 	"html/template"
 	"io"
+	"strconv"
+	"sync"
+	texttemplate "text/template"
+	"time"
 )
 
 // Strategy logging strategy in the email
@@ -20,22 +26,159 @@ type Strategy struct {
 	Message  *gomail.Message
 	Template *template.Template
 	io.Writer
+	digest  *digest
+	limiter *limiter
+	quiet   *quietHours
+	subject *texttemplate.Template
+	values  map[string]string
+	headers map[string][]string
+	rules   []Rule
+	// attachments of the entry
+	attachStack  bool
+	attachFields bool
+	recent       *Recent
+	// suppressed messages are reported in the next sent email or by the summary
+	// when sending is possible again
+	mu         sync.Mutex
+	suppressed int
+	summary    *time.Timer
 }
 
+// now returns the current time, tests replace it
+var now = time.Now
+
+// afterFunc schedules the summary of the suppressed messages, tests replace it
+var afterFunc = time.AfterFunc
+
+// Option configures the email strategy
+type Option func(s *Strategy)
+
 //Get waiting for a parameter ess in format host:port;username;password
-func Get(sender mailSender.AsyncSender, msg *gomail.Message, tpl *template.Template) io.Writer {
-	return &Strategy{
+func Get(sender mailSender.AsyncSender, msg *gomail.Message, tpl *template.Template, options ...Option) io.Writer {
+	s := &Strategy{
 		sender:   sender,
 		Message:  msg,
 		Template: tpl,
 	}
+	for _, option := range options {
+		option(s)
+	}
+	return s
 }
 
 func (s *Strategy) Write(p []byte) (n int, err error) {
+	return s.WriteEntry(nil, p)
+}
+
+// WriteEntry sends the message, in the digest mode it is collected until the digest is sent
+func (s *Strategy) WriteEntry(e *entry.Entry, p []byte) (n int, err error) {
+	if s.quiet != nil && !s.quiet.allow(e, now()) {
+		s.mu.Lock()
+		s.suppress(1, s.quiet.endAfter(now()))
+		s.mu.Unlock()
+		return len(p), nil
+	}
+	if s.digest == nil {
+		s.send(e, string(p), 1)
+		return len(p), nil
+	}
+	if s.digest.add(e, string(p), s.Flush) {
+		s.Flush()
+	}
+	return len(p), nil
+}
+
+// send sends the email of the count messages unless the rate limit is reached.
+// The template gets the number of the suppressed messages as Suppressed, they are also noted in Data
+func (s *Strategy) send(e *entry.Entry, data string, count int) {
+	s.mu.Lock()
+	if s.limiter != nil && !s.limiter.allow(now()) {
+		s.suppress(count, s.limiter.reopen(now()))
+		s.mu.Unlock()
+		return
+	}
+	suppressed := s.takeSuppressed()
+	s.mu.Unlock()
+	s.deliver(e, data, count, suppressed)
+}
+
+// deliver passes the email to the sender, a summary of the suppressed messages has no entry and no data
+func (s *Strategy) deliver(e *entry.Entry, data string, count int, suppressed int) {
+	data = s.withoutStack(e, data)
+	values := s.entryValues(e, data)
+	if s.digest != nil {
+		values["Count"] = strconv.Itoa(count)
+	}
+	if suppressed > 0 {
+		note := suppressedNote(suppressed)
+		values["Data"] = note + "\n" + data
+		values["Suppressed"] = strconv.Itoa(suppressed)
+		if count == 0 {
+			values["Message"] = note
+		}
+	}
+	emailData := mailSender.EmailData{}
+	for key, value := range values {
+		emailData[key] = value
+	}
 	s.sender.SendAsync(mailSender.Message{
-		Message:  s.Message,
+		Message:  s.message(values, s.recipients(e, data), s.attachments(e)),
 		Template: s.Template,
-		Data:     mailSender.EmailData{"Data": string(p)},
+		Data:     emailData,
 	})
-	return len(p), nil
+}
+
+// suppress counts the messages and schedules the summary at the time when sending is possible again,
+// it is called under the mutex
+func (s *Strategy) suppress(count int, reopen time.Time) {
+	s.suppressed += count
+	if s.summary == nil {
+		s.summary = afterFunc(reopen.Sub(now()), s.sendSummary)
+	}
+}
+
+// takeSuppressed returns the number of the suppressed messages and cancels the summary,
+// it is called under the mutex
+func (s *Strategy) takeSuppressed() int {
+	suppressed := s.suppressed
+	s.suppressed = 0
+	if s.summary != nil {
+		s.summary.Stop()
+		s.summary = nil
+	}
+	return suppressed
+}
+
+// sendSummary sends the number of the suppressed messages if no email reported them yet
+func (s *Strategy) sendSummary() {
+	s.mu.Lock()
+	s.summary = nil
+	if s.suppressed == 0 {
+		s.mu.Unlock()
+		return
+	}
+	if s.quiet != nil && s.quiet.active(now()) {
+		s.suppress(0, s.quiet.endAfter(now()))
+		s.mu.Unlock()
+		return
+	}
+	s.mu.Unlock()
+	s.send(nil, "", 0)
+}
+
+// closeSummary sends the summary of the suppressed messages regardless of the limits
+func (s *Strategy) closeSummary() {
+	s.mu.Lock()
+	suppressed := s.takeSuppressed()
+	s.mu.Unlock()
+	if suppressed > 0 {
+		s.deliver(nil, "", 0, suppressed)
+	}
+}
+
+func suppressedNote(count int) string {
+	if count == 1 {
+		return "1 message was suppressed"
+	}
+	return fmt.Sprintf("%d messages were suppressed", count)
 }
@@ -0,0 +1,113 @@
+////////////////////////////////////////////////////////////////////////////////
+// Author:   Nikita Koryabkin
+// Email:    Nikita@Koryabk.in
+// Telegram: https://t.me/Apologiz
+////////////////////////////////////////////////////////////////////////////////
+
+package email
+
+import (
+	"time"
+
+	"github.com/mylockerteam/alog/entry"
+)
+
+// limiter sent emails during the last hour
+type limiter struct {
+	perMinute int
+	perHour   int
+	sent      []time.Time
+}
+
+// quietHours daily window when only the kept logger types are sent.
+// start and end are offsets from midnight, the window may cross it
+type quietHours struct {
+	start time.Duration
+	end   time.Duration
+	keep  []uint
+}
+
+// WithRateLimit limits the number of emails per minute and per hour, zero means no limit.
+// Messages over the limit are not sent, their number is reported in the next sent email
+// or in a summary when the limit allows sending again
+func WithRateLimit(perMinute, perHour int) Option {
+	return func(s *Strategy) {
+		s.limiter = &limiter{perMinute: perMinute, perHour: perHour}
+	}
+}
+
+// WithQuietHours sends only messages of the kept logger types between start and end in local time,
+// e.g. WithQuietHours(22*time.Hour, 7*time.Hour, alog.Err). Other messages are counted
+// and reported in the next sent email or in a summary at the end of the window
+func WithQuietHours(start, end time.Duration, keep ...uint) Option {
+	return func(s *Strategy) {
+		s.quiet = &quietHours{start: start, end: end, keep: keep}
+	}
+}
+
+// allow reports whether an email may be sent at the time and records it
+func (l *limiter) allow(t time.Time) bool {
+	i := 0
+	for i < len(l.sent) && t.Sub(l.sent[i]) >= time.Hour {
+		i++
+	}
+	l.sent = l.sent[i:]
+	lastMinute := 0
+	for _, sent := range l.sent {
+		if t.Sub(sent) < time.Minute {
+			lastMinute++
+		}
+	}
+	if (l.perMinute > 0 && lastMinute >= l.perMinute) || (l.perHour > 0 && len(l.sent) >= l.perHour) {
+		return false
+	}
+	l.sent = append(l.sent, t)
+	return true
+}
+
+// reopen returns the time when the next email is allowed
+func (l *limiter) reopen(t time.Time) time.Time {
+	at := t
+	if n := len(l.sent); l.perHour > 0 && n >= l.perHour {
+		at = l.sent[n-l.perHour].Add(time.Hour)
+	}
+	if n := len(l.sent); l.perMinute > 0 && n >= l.perMinute {
+		if minute := l.sent[n-l.perMinute].Add(time.Minute); minute.After(at) {
+			at = minute
+		}
+	}
+	return at
+}
+
+// allow reports whether the entry may be sent at the time, messages without an entry
+// are not sent during the quiet hours
+func (q *quietHours) allow(e *entry.Entry, t time.Time) bool {
+	if !q.active(t) {
+		return true
+	}
+	for _, keep := range q.keep {
+		if e != nil && e.Level == keep {
+			return true
+		}
+	}
+	return false
+}
+
+// endAfter returns the next end of the window in local time
+func (q *quietHours) endAfter(t time.Time) time.Time {
+	t = t.Local()
+	end := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.Local).Add(q.end)
+	if !end.After(t) {
+		end = end.AddDate(0, 0, 1)
+	}
+	return end
+}
+
+func (q *quietHours) active(t time.Time) bool {
+	t = t.Local()
+	offset := time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute + time.Duration(t.Second())*time.Second
+	if q.start <= q.end {
+		return offset >= q.start && offset < q.end
+	}
+	return offset >= q.start || offset < q.end
+}
@@ -0,0 +1,231 @@
+////////////////////////////////////////////////////////////////////////////////
+// Author:   Nikita Koryabkin
+// Email:    Nikita@Koryabk.in
+// Telegram: https://t.me/Apologiz
+////////////////////////////////////////////////////////////////////////////////
+
+package email
+
+import (
+	"html/template"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/mylockerteam/alog/entry"
+	"github.com/mylockerteam/mailSender"
+	"gopkg.in/gomail.v2"
+)
+
+func TestWithRateLimit(t *testing.T) {
+	defer func() { now = time.Now }()
+	current := time.Date(2026, time.October, 19, 12, 0, 0, 0, time.Local)
+	now = func() time.Time { return current }
+	sender := &chanSender{messages: make(chan mailSender.Message, 10)}
+	tpl, _ := template.New("test").Parse("{{ .Data }}")
+	s := Get(sender, gomail.NewMessage(), tpl, WithRateLimit(2, 3))
+	for _, minute := range []time.Duration{0, 0, 0, 0, time.Minute, time.Minute, time.Hour + time.Second} {
+		current = current.Add(minute)
+		_, _ = s.Write([]byte("refused"))
+	}
+	close(sender.messages)
+	var got []mailSender.EmailData
+	for message := range sender.messages {
+		got = append(got, message.Data)
+	}
+	want := []mailSender.EmailData{
+		{"Data": "refused", "Message": "refused"},
+		{"Data": "refused", "Message": "refused"},
+		{"Data": "2 messages were suppressed\nrefused", "Message": "refused", "Suppressed": "2"},
+		{"Data": "1 message was suppressed\nrefused", "Message": "refused", "Suppressed": "1"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("sent %v, want %v", got, want)
+	}
+}
+
+func Test_quietHours_allow(t *testing.T) {
+	night := &quietHours{start: 22 * time.Hour, end: 7 * time.Hour, keep: []uint{2}}
+	lunch := &quietHours{start: 12 * time.Hour, end: 13 * time.Hour}
+	day := func(hour, minute int) time.Time {
+		return time.Date(2026, time.October, 19, hour, minute, 0, 0, time.Local)
+	}
+	tests := []struct {
+		name  string
+		quiet *quietHours
+		entry *entry.Entry
+		time  time.Time
+		want  bool
+	}{
+		{
+			quiet: night,
+			entry: &entry.Entry{Level: 0},
+			time:  day(21, 59),
+			want:  true,
+		},
+		{
+			quiet: night,
+			entry: &entry.Entry{Level: 0},
+			time:  day(23, 0),
+			want:  false,
+		},
+		{
+			quiet: night,
+			entry: &entry.Entry{Level: 2},
+			time:  day(3, 0),
+			want:  true,
+		},
+		{
+			quiet: night,
+			time:  day(6, 59),
+			want:  false,
+		},
+		{
+			quiet: night,
+			time:  day(7, 0),
+			want:  true,
+		},
+		{
+			quiet: lunch,
+			entry: &entry.Entry{Level: 2},
+			time:  day(12, 30),
+			want:  false,
+		},
+		{
+			quiet: lunch,
+			time:  day(13, 30),
+			want:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.quiet.allow(tt.entry, tt.time); got != tt.want {
+				t.Errorf("quietHours.allow() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithQuietHours(t *testing.T) {
+	defer func() { now = time.Now }()
+	current := time.Date(2026, time.October, 19, 23, 0, 0, 0, time.Local)
+	now = func() time.Time { return current }
+	sender := &chanSender{messages: make(chan mailSender.Message, 10)}
+	tpl, _ := template.New("test").Parse("{{ .Data }}")
+	s := Get(sender, gomail.NewMessage(), tpl, WithQuietHours(22*time.Hour, 7*time.Hour, 2)).(*Strategy)
+	_, _ = s.WriteEntry(&entry.Entry{Level: 0, LevelName: "Info", Message: "info"}, []byte("info"))
+	_, _ = s.WriteEntry(&entry.Entry{Level: 2, LevelName: "Error", Message: "error"}, []byte("error"))
+	got := <-sender.messages
+	want := mailSender.EmailData{
+		"Data":       "1 message was suppressed\nerror",
+		"Level":      "Error",
+		"Message":    "error",
+		"Suppressed": "1",
+	}
+	if !reflect.DeepEqual(got.Data, want) {
+		t.Errorf("sent %v, want %v", got.Data, want)
+	}
+}
+
+// scheduled replaces afterFunc and keeps the scheduled functions with their delays
+type scheduled struct {
+	delays []time.Duration
+	funcs  []func()
+}
+
+func (sc *scheduled) afterFunc(d time.Duration, f func()) *time.Timer {
+	sc.delays = append(sc.delays, d)
+	sc.funcs = append(sc.funcs, f)
+	return time.AfterFunc(time.Hour, func() {})
+}
+
+func TestStrategy_summary(t *testing.T) {
+	defer func() { now, afterFunc = time.Now, time.AfterFunc }()
+	tests := []struct {
+		name    string
+		start   time.Time
+		options []Option
+		writes  int
+		advance time.Duration
+		close   bool
+		delays  []time.Duration
+		want    []mailSender.EmailData
+	}{
+		{
+			name:    "rate limit",
+			start:   time.Date(2026, time.October, 19, 12, 0, 0, 0, time.Local),
+			options: []Option{WithRateLimit(1, 0)},
+			writes:  3,
+			advance: time.Minute,
+			delays:  []time.Duration{time.Minute},
+			want: []mailSender.EmailData{
+				{"Data": "refused", "Message": "refused"},
+				{"Data": "2 messages were suppressed\n", "Message": "2 messages were suppressed", "Suppressed": "2"},
+			},
+		},
+		{
+			name:    "still limited",
+			start:   time.Date(2026, time.October, 19, 12, 0, 0, 0, time.Local),
+			options: []Option{WithRateLimit(1, 0)},
+			writes:  2,
+			advance: time.Second,
+			delays:  []time.Duration{time.Minute, time.Minute - time.Second},
+			want: []mailSender.EmailData{
+				{"Data": "refused", "Message": "refused"},
+			},
+		},
+		{
+			name:    "quiet hours",
+			start:   time.Date(2026, time.October, 19, 23, 0, 0, 0, time.Local),
+			options: []Option{WithQuietHours(22*time.Hour, 7*time.Hour)},
+			writes:  2,
+			advance: 8 * time.Hour,
+			delays:  []time.Duration{8 * time.Hour},
+			want: []mailSender.EmailData{
+				{"Data": "2 messages were suppressed\n", "Message": "2 messages were suppressed", "Suppressed": "2"},
+			},
+		},
+		{
+			name:    "close",
+			start:   time.Date(2026, time.October, 19, 23, 0, 0, 0, time.Local),
+			options: []Option{WithQuietHours(22*time.Hour, 7*time.Hour)},
+			writes:  1,
+			close:   true,
+			delays:  []time.Duration{8 * time.Hour},
+			want: []mailSender.EmailData{
+				{"Data": "1 message was suppressed\n", "Message": "1 message was suppressed", "Suppressed": "1"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			current := tt.start
+			now = func() time.Time { return current }
+			sc := &scheduled{}
+			afterFunc = sc.afterFunc
+			sender := &chanSender{messages: make(chan mailSender.Message, 10)}
+			tpl, _ := template.New("test").Parse("{{ .Data }}")
+			s := Get(sender, gomail.NewMessage(), tpl, tt.options...).(*Strategy)
+			for i := 0; i < tt.writes; i++ {
+				_, _ = s.Write([]byte("refused"))
+			}
+			current = current.Add(tt.advance)
+			if tt.close {
+				_ = s.Close()
+			} else if len(sc.funcs) > 0 {
+				sc.funcs[0]()
+			}
+			close(sender.messages)
+			var got []mailSender.EmailData
+			for message := range sender.messages {
+				got = append(got, message.Data)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("sent %v, want %v", got, tt.want)
+			}
+			if !reflect.DeepEqual(sc.delays, tt.delays) {
+				t.Errorf("summary scheduled after %v, want %v", sc.delays, tt.delays)
+			}
+		})
+	}
+}
@@ -0,0 +1,123 @@
+////////////////////////////////////////////////////////////////////////////////
+// Author:   Nikita Koryabkin
+// Email:    Nikita@Koryabk.in
+// Telegram: https://t.me/Apologiz
+////////////////////////////////////////////////////////////////////////////////
+
+package email
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/mylockerteam/alog/entry"
+)
+
+// Rule sends the matching entries to the recipients.
+// All set conditions must match, a rule without conditions matches every entry
+type Rule struct {
+	// Levels logger types of the entry
+	Levels []uint
+	// Message matches the message of the entry or the text of a plain write
+	Message *regexp.Regexp
+	// Package import path of the caller's package or its parent, e.g. github.com/acme/billing
+	Package string
+	// Field and Value the entry has the field with the value, e.g. component=billing
+	Field string
+	Value string
+	// To, Cc and Bcc recipients of the email, Bcc ones are not visible to the others
+	To  []string
+	Cc  []string
+	Bcc []string
+}
+
+// WithRules picks the recipients of every email by the rules, the recipients of all matching rules
+// replace the recipients of the message. Without a matching rule the message's recipients are used.
+// A digest is routed by its first entry
+func WithRules(rules ...Rule) Option {
+	return func(s *Strategy) {
+		s.rules = rules
+	}
+}
+
+// recipients returns the recipients of the matching rules by the header without duplicates,
+// an address is put in the most visible of its headers
+func (s *Strategy) recipients(e *entry.Entry, text string) map[string][]string {
+	var matched []Rule
+	for _, rule := range s.rules {
+		if rule.match(e, text) {
+			matched = append(matched, rule)
+		}
+	}
+	routed := map[string][]string{}
+	seen := map[string]bool{}
+	for _, field := range []string{"To", "Cc", "Bcc"} {
+		for _, rule := range matched {
+			for _, recipient := range rule.header(field) {
+				if !seen[recipient] {
+					seen[recipient] = true
+					routed[field] = append(routed[field], recipient)
+				}
+			}
+		}
+	}
+	return routed
+}
+
+func (r *Rule) header(field string) []string {
+	switch field {
+	case "Cc":
+		return r.Cc
+	case "Bcc":
+		return r.Bcc
+	}
+	return r.To
+}
+
+func (r *Rule) match(e *entry.Entry, text string) bool {
+	if e == nil {
+		return len(r.Levels) == 0 && r.Package == "" && r.Field == "" && (r.Message == nil || r.Message.MatchString(text))
+	}
+	return r.matchLevel(e.Level) &&
+		(r.Message == nil || r.Message.MatchString(e.Message)) &&
+		(r.Package == "" || matchPackage(r.Package, callerPackage(e.Caller.Function))) &&
+		(r.Field == "" || r.matchField(e.Fields))
+}
+
+func (r *Rule) matchLevel(level uint) bool {
+	if len(r.Levels) == 0 {
+		return true
+	}
+	for _, l := range r.Levels {
+		if l == level {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Rule) matchField(fields []entry.Field) bool {
+	for _, field := range fields {
+		if field.Key == r.Field && fmt.Sprint(field.Value) == r.Value {
+			return true
+		}
+	}
+	return false
+}
+
+// matchPackage reports whether the package is the pattern or its subpackage
+func matchPackage(pattern string, pkg string) bool {
+	return pkg == pattern || strings.HasPrefix(pkg, pattern+"/")
+}
+
+// callerPackage returns the import path of the function's package,
+// e.g. github.com/acme/billing for github.com/acme/billing.(*Invoice).Pay.
+// Dots of the last path element are escaped in function names, e.g. gopkg.in/yaml%2ev2.Unmarshal
+func callerPackage(function string) string {
+	slash := strings.LastIndexByte(function, '/') + 1
+	if dot := strings.IndexByte(function[slash:], '.'); dot >= 0 {
+		function = function[:slash+dot]
+	}
+	return strings.Replace(function, "%2e", ".", -1)
+}
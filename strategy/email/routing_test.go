@@ -0,0 +1,131 @@
+////////////////////////////////////////////////////////////////////////////////
+// Author:   Nikita Koryabkin
+// Email:    Nikita@Koryabk.in
+// Telegram: https://t.me/Apologiz
+////////////////////////////////////////////////////////////////////////////////
+
+package email
+
+import (
+	"html/template"
+	"reflect"
+	"regexp"
+	"testing"
+
+	"github.com/mylockerteam/alog/entry"
+	"github.com/mylockerteam/mailSender"
+	"gopkg.in/gomail.v2"
+)
+
+func TestWithRules(t *testing.T) {
+	rules := []Rule{
+		{
+			Field: "component",
+			Value: "billing",
+			To:    []string{"billing-oncall@example.com"},
+		},
+		{
+			Levels:  []uint{2},
+			Message: regexp.MustCompile(`(?i)database`),
+			To:      []string{"dba@example.com", "billing-oncall@example.com"},
+		},
+		{
+			Package: "github.com/acme/payments",
+			To:      []string{"payments@example.com"},
+		},
+		{
+			Message: regexp.MustCompile(`fraud`),
+			Cc:      []string{"risk@example.com"},
+			Bcc:     []string{"audit@example.com", "payments@example.com"},
+		},
+	}
+	msg := gomail.NewMessage()
+	msg.SetHeader("From", "no-reply@example.com")
+	msg.SetHeader("Bcc", "all@example.com")
+	msg.SetHeader("Subject", "Alert")
+	tests := []struct {
+		name  string
+		entry *entry.Entry
+		text  string
+		want  map[string][]string
+	}{
+		{
+			entry: &entry.Entry{Level: 2, Message: "database is down", Fields: []entry.Field{{Key: "component", Value: "billing"}}},
+			want:  map[string][]string{"To": {"billing-oncall@example.com", "dba@example.com"}, "Subject": {"Alert"}},
+		},
+		{
+			entry: &entry.Entry{Level: 1, Message: "database is slow", Caller: entry.Caller{Function: "github.com/acme/payments/card.(*Card).Charge"}},
+			want:  map[string][]string{"To": {"payments@example.com"}, "Subject": {"Alert"}},
+		},
+		{
+			entry: &entry.Entry{Level: 2, Message: "timeout", Caller: entry.Caller{Function: "github.com/acme/paymentsv2.Pay"}},
+			want:  map[string][]string{"Bcc": {"all@example.com"}, "Subject": {"Alert"}},
+		},
+		{
+			entry: &entry.Entry{Level: 2, Message: "fraud suspected", Caller: entry.Caller{Function: "github.com/acme/payments.Pay"}},
+			want: map[string][]string{
+				"To":      {"payments@example.com"},
+				"Cc":      {"risk@example.com"},
+				"Bcc":     {"audit@example.com"},
+				"Subject": {"Alert"},
+			},
+		},
+		{
+			text: "fraud suspected",
+			want: map[string][]string{"Cc": {"risk@example.com"}, "Bcc": {"audit@example.com", "payments@example.com"}, "Subject": {"Alert"}},
+		},
+		{
+			text: "database is down",
+			want: map[string][]string{"Bcc": {"all@example.com"}, "Subject": {"Alert"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sender := &chanSender{messages: make(chan mailSender.Message, 1)}
+			tpl, _ := template.New("test").Parse("{{ .Data }}")
+			s := Get(sender, msg, tpl, WithRules(rules...)).(*Strategy)
+			_, _ = s.WriteEntry(tt.entry, []byte(tt.text))
+			got := (<-sender.messages).Message
+			for _, field := range []string{"To", "Cc", "Bcc", "Subject"} {
+				if value := got.GetHeader(field); !reflect.DeepEqual(value, tt.want[field]) && (len(value) != 0 || tt.want[field] != nil) {
+					t.Errorf("%s = %v, want %v", field, value, tt.want[field])
+				}
+			}
+			if got.GetHeader("From")[0] != "no-reply@example.com" {
+				t.Errorf("From = %v", got.GetHeader("From"))
+			}
+		})
+	}
+}
+
+func Test_callerPackage(t *testing.T) {
+	tests := []struct {
+		name     string
+		function string
+		want     string
+	}{
+		{
+			function: "github.com/acme/billing.(*Invoice).Pay",
+			want:     "github.com/acme/billing",
+		},
+		{
+			function: "gopkg.in/yaml%2ev2.Unmarshal",
+			want:     "gopkg.in/yaml.v2",
+		},
+		{
+			function: "main.main",
+			want:     "main",
+		},
+		{
+			function: "",
+			want:     "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := callerPackage(tt.function); got != tt.want {
+				t.Errorf("callerPackage() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
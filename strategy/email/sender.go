@@ -0,0 +1,153 @@
+////////////////////////////////////////////////////////////////////////////////
+// Author:   Nikita Koryabkin
+// Email:    Nikita@Koryabk.in
+// Telegram: https://t.me/Apologiz
+////////////////////////////////////////////////////////////////////////////////
+
+package email
+
+import (
+	"bytes"
+	"errors"
+	"sync"
+	"sync/atomic"
+
+	"github.com/mylockerteam/mailSender"
+	"gopkg.in/gomail.v2"
+)
+
+const defaultQueueSize = 64
+
+var errSenderClosed = errors.New("email sender is closed")
+var errMessageNotDefined = errors.New("email message is not defined")
+
+// Sender delivers emails in the background like mailSender and reports the result of every delivery.
+// Pass it to Get instead of the mailSender one
+type Sender struct {
+	dealer   mailSender.GomailDealer
+	report   func(msg *gomail.Message, err error)
+	messages chan mailSender.Message
+	sent     uint64
+	failed   uint64
+
+	mu     sync.RWMutex
+	closed bool
+	done   chan struct{}
+	closer gomail.SendCloser
+}
+
+// SenderOption configures the sender
+type SenderOption func(s *Sender)
+
+// NewSender starts the sender, the connection is dialed on the first email
+// and again after a failed delivery
+func NewSender(dealer mailSender.GomailDealer, options ...SenderOption) *Sender {
+	s := &Sender{
+		dealer:   dealer,
+		messages: make(chan mailSender.Message, defaultQueueSize),
+		done:     make(chan struct{}),
+	}
+	for _, option := range options {
+		option(s)
+	}
+	go s.reader()
+	return s
+}
+
+// WithReport calls the function after every delivery, the error is nil on success.
+// It is called from the sender goroutine, e.g. to write failed alerts to another logger
+func WithReport(report func(msg *gomail.Message, err error)) SenderOption {
+	return func(s *Sender) {
+		s.report = report
+	}
+}
+
+// WithQueueSize sets the number of emails waiting for delivery, SendAsync blocks when the queue is full
+func WithQueueSize(size int) SenderOption {
+	return func(s *Sender) {
+		s.messages = make(chan mailSender.Message, size)
+	}
+}
+
+// SendAsync queues the email, after Close it is reported as failed
+func (s *Sender) SendAsync(message mailSender.Message) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.closed {
+		s.result(message.Message, errSenderClosed)
+		return
+	}
+	s.messages <- message
+}
+
+// Sent returns the number of delivered emails
+func (s *Sender) Sent() uint64 {
+	return atomic.LoadUint64(&s.sent)
+}
+
+// Failed returns the number of emails which were not delivered
+func (s *Sender) Failed() uint64 {
+	return atomic.LoadUint64(&s.failed)
+}
+
+// Close delivers the queued emails and closes the connection
+func (s *Sender) Close() error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil
+	}
+	s.closed = true
+	close(s.messages)
+	s.mu.Unlock()
+	<-s.done
+	if s.closer != nil {
+		return s.closer.Close()
+	}
+	return nil
+}
+
+func (s *Sender) reader() {
+	defer close(s.done)
+	for message := range s.messages {
+		s.result(message.Message, s.deliver(message))
+	}
+}
+
+// deliver renders the body like mailSender and sends the email, the connection is closed on error
+func (s *Sender) deliver(message mailSender.Message) error {
+	if message.Message == nil {
+		return errMessageNotDefined
+	}
+	if message.Template != nil {
+		body := new(bytes.Buffer)
+		if err := message.Template.Execute(body, message.Data); err != nil {
+			return err
+		}
+		message.Message.SetBody("text/html", body.String())
+	}
+	if s.closer == nil {
+		closer, err := s.dealer.Dial()
+		if err != nil {
+			return err
+		}
+		s.closer = closer
+	}
+	if err := gomail.Send(s.closer, message.Message); err != nil {
+		s.closer.Close()
+		s.closer = nil
+		return err
+	}
+	return nil
+}
+
+func (s *Sender) result(msg *gomail.Message, err error) {
+	if err != nil {
+		atomic.AddUint64(&s.failed, 1)
+	} else {
+		atomic.AddUint64(&s.sent, 1)
+	}
+	if s.report != nil {
+		s.report(msg, err)
+	}
+}
@@ -0,0 +1,80 @@
+////////////////////////////////////////////////////////////////////////////////
+// Author:   Nikita Koryabkin
+// Email:    Nikita@Koryabk.in
+// Telegram: https://t.me/Apologiz
+////////////////////////////////////////////////////////////////////////////////
+
+package email
+
+import (
+	"errors"
+	"html/template"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/mylockerteam/alog/mocks"
+	"github.com/mylockerteam/mailSender"
+	"gopkg.in/gomail.v2"
+)
+
+const testMsg = "Hello, ALog!"
+
+func TestSender(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	errRejected := errors.New("535 authentication failed")
+	errDial := errors.New("connection refused")
+
+	msg := gomail.NewMessage()
+	msg.SetHeader("From", "no-reply@example.com")
+	msg.SetHeader("To", "test@example.com")
+
+	mockSendCloser := mocks.NewMockSendCloser(mockCtrl)
+	gomock.InOrder(
+		mockSendCloser.EXPECT().Send("no-reply@example.com", []string{"test@example.com"}, msg).Return(nil),
+		mockSendCloser.EXPECT().Send("no-reply@example.com", []string{"test@example.com"}, msg).Return(errRejected),
+		mockSendCloser.EXPECT().Close().Return(nil),
+		mockSendCloser.EXPECT().Send("no-reply@example.com", []string{"test@example.com"}, msg).Return(nil),
+		mockSendCloser.EXPECT().Close().Return(nil),
+	)
+	mockDealer := mocks.NewMockGomailDealer(mockCtrl)
+	gomock.InOrder(
+		mockDealer.EXPECT().Dial().Return(mockSendCloser, nil),
+		mockDealer.EXPECT().Dial().Return(nil, errDial),
+		mockDealer.EXPECT().Dial().Return(mockSendCloser, nil),
+	)
+
+	var mu sync.Mutex
+	var got []error
+	sender := NewSender(mockDealer, WithReport(func(m *gomail.Message, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		got = append(got, err)
+	}))
+	tpl, _ := template.New("test").Parse("<pre>{{ .Data }}</pre>")
+	strategy := Get(sender, msg, tpl)
+	for i := 0; i < 4; i++ {
+		if _, err := strategy.Write([]byte(testMsg)); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+	}
+	if err := sender.Close(); err != nil {
+		t.Errorf("Close() error = %v", err)
+	}
+	sender.SendAsync(mailSender.Message{Message: msg})
+
+	want := []error{nil, errRejected, errDial, nil, errSenderClosed}
+	if len(got) != len(want) {
+		t.Fatalf("reported %v, want %v", got, want)
+	}
+	for i := range want {
+		if (got[i] == nil) != (want[i] == nil) || (got[i] != nil && !strings.Contains(got[i].Error(), want[i].Error())) {
+			t.Errorf("reported %v, want %v", got[i], want[i])
+		}
+	}
+	if sender.Sent() != 2 || sender.Failed() != 3 {
+		t.Errorf("Sent() = %d, Failed() = %d, want 2 and 3", sender.Sent(), sender.Failed())
+	}
+}
@@ -0,0 +1,129 @@
+////////////////////////////////////////////////////////////////////////////////
+// Author:   Nikita Koryabkin
+// Email:    Nikita@Koryabk.in
+// Telegram: https://t.me/Apologiz
+////////////////////////////////////////////////////////////////////////////////
+
+package email
+
+import (
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/mylockerteam/alog/entry"
+	"github.com/mylockerteam/alog/internal/diag"
+	"gopkg.in/gomail.v2"
+)
+
+// copiedHeaders are copied from the message of the strategy to the new messages.
+// gomail doesn't list the headers of a message, other ones are set by WithHeaders
+var copiedHeaders = []string{"From", "Sender", "Reply-To", "To", "Cc", "Bcc", "Importance", "Priority", "X-Priority"}
+
+// recipientHeaders are replaced by the routed recipients
+var recipientHeaders = map[string]bool{"To": true, "Cc": true, "Bcc": true}
+
+// ParseSubject parses the subject template, it has the truncate function and missing values are empty,
+// e.g. [{{.Level}}] {{.Service}}: {{.Message | truncate 80}}
+func ParseSubject(text string) (*template.Template, error) {
+	return template.New("subject").Option("missingkey=zero").Funcs(template.FuncMap{"truncate": truncate}).Parse(text)
+}
+
+// WithSubject renders the subject of every email by the template.
+// The subject and the body templates get Level, Time, Caller, Message, Fields and Stack of the entry,
+// Data with the written text and the values of WithValues
+func WithSubject(subject *template.Template) Option {
+	return func(s *Strategy) {
+		s.subject = subject
+	}
+}
+
+// WithValues adds constant values to the data of the templates, e.g. Service
+func WithValues(values map[string]string) Option {
+	return func(s *Strategy) {
+		s.values = values
+	}
+}
+
+// WithHeaders sets the headers on every email, e.g. X-Mailer or List-Id.
+// They replace the headers of the message of the strategy
+func WithHeaders(headers map[string][]string) Option {
+	return func(s *Strategy) {
+		s.headers = headers
+	}
+}
+
+// entryValues returns the data of the templates, the message of a plain write is its text
+func (s *Strategy) entryValues(e *entry.Entry, data string) map[string]string {
+	values := map[string]string{}
+	for key, value := range s.values {
+		values[key] = value
+	}
+	values["Data"] = data
+	if e == nil {
+		values["Message"] = strings.TrimSuffix(data, "\n")
+		return values
+	}
+	values["Level"] = e.LevelName
+	values["Message"] = e.Message
+	if !e.Time.IsZero() {
+		values["Time"] = e.Time.Format(time.RFC3339)
+	}
+	if e.Caller.Defined() {
+		values["Caller"] = e.Caller.String()
+	}
+	if len(e.Fields) > 0 {
+		values["Fields"] = entry.FormatFields(e.Fields)
+	}
+	if e.Stack != "" {
+		values["Stack"] = e.Stack
+	}
+	return values
+}
+
+// message returns the message of the strategy. With a subject template, headers, routed recipients
+// or attachments it is a new message with the copiedHeaders of the strategy's one and the headers
+// of WithHeaders, the routed recipients replace To, Cc and Bcc of it
+func (s *Strategy) message(values map[string]string, routed map[string][]string, files []attachment) *gomail.Message {
+	if s.subject == nil && len(s.headers) == 0 && len(routed) == 0 && len(files) == 0 {
+		return s.Message
+	}
+	subject := s.Message.GetHeader("Subject")
+	if s.subject != nil {
+		var b strings.Builder
+		if err := s.subject.Execute(&b, values); err != nil {
+			diag.Println(err)
+		} else {
+			// headers are single lines
+			subject = []string{strings.Join(strings.Fields(b.String()), " ")}
+		}
+	}
+	msg := gomail.NewMessage()
+	for _, field := range copiedHeaders {
+		if value := s.Message.GetHeader(field); len(value) > 0 && (len(routed) == 0 || !recipientHeaders[field]) {
+			msg.SetHeader(field, value...)
+		}
+	}
+	for field, value := range s.headers {
+		if len(value) > 0 {
+			msg.SetHeader(field, value...)
+		}
+	}
+	for field, recipients := range routed {
+		msg.SetHeader(field, recipients...)
+	}
+	if len(subject) > 0 {
+		msg.SetHeader("Subject", subject...)
+	}
+	attach(msg, files)
+	return msg
+}
+
+// truncate shortens the text to the number of characters, the cut text is replaced with ...
+func truncate(length int, text string) string {
+	runes := []rune(text)
+	if length < 0 || len(runes) <= length {
+		return text
+	}
+	return string(runes[:length]) + "..."
+}
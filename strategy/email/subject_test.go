@@ -0,0 +1,121 @@
+////////////////////////////////////////////////////////////////////////////////
+// Author:   Nikita Koryabkin
+// Email:    Nikita@Koryabk.in
+// Telegram: https://t.me/Apologiz
+////////////////////////////////////////////////////////////////////////////////
+
+package email
+
+import (
+	"html/template"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mylockerteam/alog/entry"
+	"github.com/mylockerteam/mailSender"
+	"gopkg.in/gomail.v2"
+)
+
+func TestWithSubject(t *testing.T) {
+	subject, err := ParseSubject("[{{.Level}}] {{.Service}}: {{.Message | truncate 10}}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg := gomail.NewMessage()
+	msg.SetHeader("From", "no-reply@example.com")
+	msg.SetHeader("Bcc", "test@example.com")
+	msg.SetHeader("Subject", "Debug message")
+	msg.SetHeader("X-Priority", "1")
+	msg.SetHeader("X-Mailer", "test")
+	tpl, _ := template.New("test").Parse("{{ .Message }}")
+	tests := []struct {
+		name        string
+		write       func(s *Strategy)
+		wantSubject string
+		wantData    mailSender.EmailData
+	}{
+		{
+			write: func(s *Strategy) {
+				_, _ = s.WriteEntry(&entry.Entry{
+					LevelName: "Error",
+					Time:      time.Date(2026, time.October, 19, 12, 0, 0, 0, time.UTC),
+					Caller:    entry.Caller{File: "main.go", Line: 7},
+					Message:   "connection refused\nretrying",
+					Fields:    []entry.Field{{Key: "tenant", Value: "acme"}},
+					Stack:     "goroutine 1",
+				}, []byte("line\n"))
+			},
+			wantSubject: "[Error] api: connection...",
+			wantData: mailSender.EmailData{
+				"Data":    "line\n",
+				"Service": "api",
+				"Level":   "Error",
+				"Time":    "2026-10-19T12:00:00Z",
+				"Caller":  "main.go:7",
+				"Message": "connection refused\nretrying",
+				"Fields":  "tenant=acme",
+				"Stack":   "goroutine 1",
+			},
+		},
+		{
+			write: func(s *Strategy) {
+				_, _ = s.Write([]byte("plain\n"))
+			},
+			wantSubject: "[] api: plain",
+			wantData:    mailSender.EmailData{"Data": "plain\n", "Service": "api", "Message": "plain"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sender := &chanSender{messages: make(chan mailSender.Message, 1)}
+			s := Get(sender, msg, tpl, WithSubject(subject), WithValues(map[string]string{"Service": "api"}),
+				WithHeaders(map[string][]string{"X-Mailer": {"alog"}, "List-Id": {"alerts.example.com"}})).(*Strategy)
+			tt.write(s)
+			got := <-sender.messages
+			if subject := strings.Join(got.Message.GetHeader("Subject"), ""); subject != tt.wantSubject {
+				t.Errorf("subject = %q, want %q", subject, tt.wantSubject)
+			}
+			if !reflect.DeepEqual(got.Message.GetHeader("Bcc"), []string{"test@example.com"}) || got.Message == msg {
+				t.Errorf("message was not copied with the address headers")
+			}
+			if !reflect.DeepEqual(got.Message.GetHeader("X-Priority"), []string{"1"}) {
+				t.Errorf("message was not copied with the priority")
+			}
+			if !reflect.DeepEqual(got.Message.GetHeader("X-Mailer"), []string{"alog"}) || !reflect.DeepEqual(got.Message.GetHeader("List-Id"), []string{"alerts.example.com"}) {
+				t.Errorf("message has headers X-Mailer %v, List-Id %v", got.Message.GetHeader("X-Mailer"), got.Message.GetHeader("List-Id"))
+			}
+			if !reflect.DeepEqual(got.Data, tt.wantData) {
+				t.Errorf("data = %v, want %v", got.Data, tt.wantData)
+			}
+		})
+	}
+}
+
+func Test_truncate(t *testing.T) {
+	tests := []struct {
+		name   string
+		length int
+		text   string
+		want   string
+	}{
+		{
+			length: 5,
+			text:   "short",
+			want:   "short",
+		},
+		{
+			length: 3,
+			text:   "привет",
+			want:   "при...",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := truncate(tt.length, tt.text); got != tt.want {
+				t.Errorf("truncate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,132 @@
+////////////////////////////////////////////////////////////////////////////////
+// Author:   Nikita Koryabkin
+// Email:    Nikita@Koryabk.in
+// Telegram: https://t.me/Apologiz
+////////////////////////////////////////////////////////////////////////////////
+
+package file
+
+import (
+	"os"
+	"sync/atomic"
+	"time"
+
+	"github.com/mylockerteam/alog/internal/diag"
+)
+
+// SyncPolicy defines when the written data is committed to the disk
+type SyncPolicy time.Duration
+
+const (
+	// SyncNever leaves syncing to the operating system
+	SyncNever SyncPolicy = 0
+	// SyncEveryWrite flushes the buffer and syncs the file after every write
+	SyncEveryWrite SyncPolicy = -1
+)
+
+// SyncEvery flushes the buffer and syncs the file once per the interval
+func SyncEvery(interval time.Duration) SyncPolicy {
+	return SyncPolicy(interval)
+}
+
+// WithBuffer collects messages in memory and writes them when the buffer of the size is full.
+// Messages are never split between writes, those larger than the buffer are written directly
+func WithBuffer(size int) Option {
+	return func(o *options) {
+		o.bufferSize = size
+	}
+}
+
+// WithFlushInterval writes the buffered messages at least once per the interval
+func WithFlushInterval(interval time.Duration) Option {
+	return func(o *options) {
+		o.flushInterval = interval
+	}
+}
+
+// WithSyncPolicy sets when the file is synced to the disk, SyncNever by default
+func WithSyncPolicy(policy SyncPolicy) Option {
+	return func(o *options) {
+		o.syncPolicy = policy
+	}
+}
+
+// Close writes the buffered messages and closes the file.
+// The file is shared by the strategies with the same path and is closed with the last of them.
+// The File of a strategy created directly is left to its owner. A strategy releases the file once,
+// Close returns os.ErrClosed after that
+func (s *Strategy) Close() error {
+	if s.handle == nil {
+		return nil
+	}
+	if !atomic.CompareAndSwapUint32(&s.closed, 0, 1) {
+		return os.ErrClosed
+	}
+	return s.handle.release()
+}
+
+func (h *handle) writeBuffered(p []byte) (n int, err error) {
+	if len(h.buffer)+len(p) > h.options.bufferSize {
+		if err := h.flush(); err != nil {
+			return 0, err
+		}
+	}
+	if len(p) >= h.options.bufferSize {
+		return h.writeFile(p)
+	}
+	h.buffer = append(h.buffer, p...)
+	return len(p), nil
+}
+
+// flush writes the buffered messages. They are dropped on error,
+// so a broken file doesn't make the buffer grow
+func (h *handle) flush() error {
+	if len(h.buffer) == 0 || h.file == nil {
+		return nil
+	}
+	_, err := h.writeFile(h.buffer)
+	h.buffer = h.buffer[:0]
+	return err
+}
+
+// flusher flushes the buffer and syncs the file by the intervals until the handle is closed
+func (h *handle) flusher() {
+	flush, stopFlush := tick(h.options.flushInterval)
+	defer stopFlush()
+	sync, stopSync := tick(time.Duration(h.options.syncPolicy))
+	defer stopSync()
+	for {
+		select {
+		case <-flush:
+			h.periodic(false)
+		case <-sync:
+			h.periodic(true)
+		case <-h.done:
+			return
+		}
+	}
+}
+
+func (h *handle) periodic(sync bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed || h.file == nil {
+		return
+	}
+	err := h.flush()
+	if err == nil && sync {
+		err = h.file.Sync()
+	}
+	if err != nil {
+		diag.Println(err)
+	}
+}
+
+// tick returns the channel of the ticker, nil when the interval is not positive
+func tick(interval time.Duration) (<-chan time.Time, func()) {
+	if interval <= 0 {
+		return nil, func() {}
+	}
+	ticker := time.NewTicker(interval)
+	return ticker.C, ticker.Stop
+}
@@ -0,0 +1,129 @@
+////////////////////////////////////////////////////////////////////////////////
+// Author:   Nikita Koryabkin
+// Email:    Nikita@Koryabk.in
+// Telegram: https://t.me/Apologiz
+////////////////////////////////////////////////////////////////////////////////
+
+package file
+
+import (
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/mylockerteam/alog/util"
+	"github.com/spf13/afero"
+)
+
+func readTestFile(name string) string {
+	data, _ := afero.ReadFile(fs, name)
+	return string(data)
+}
+
+func TestWithBuffer(t *testing.T) {
+	filePath := fmt.Sprintf("/tmp/%s/error.log", util.RandString(10))
+	strategy := Get(filePath, WithBuffer(10)).(*Strategy)
+	tests := []struct {
+		name string
+		p    string
+		want string
+	}{
+		{
+			p:    "12345",
+			want: "",
+		},
+		{
+			p:    "67890",
+			want: "",
+		},
+		{
+			p:    "a",
+			want: "1234567890",
+		},
+		{
+			p:    "bcdefghijklmnop",
+			want: "1234567890abcdefghijklmnop",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if n, err := strategy.Write([]byte(tt.p)); err != nil || n != len(tt.p) {
+				t.Fatalf("Write() = %d, error = %v", n, err)
+			}
+			if got := readTestFile(filePath); got != tt.want {
+				t.Errorf("file contains %q, want %q", got, tt.want)
+			}
+		})
+	}
+	if _, err := strategy.Write([]byte("q")); err != nil {
+		t.Fatal(err)
+	}
+	if err := strategy.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if got, want := readTestFile(filePath), "1234567890abcdefghijklmnopq"; got != want {
+		t.Errorf("Close() file contains %q, want %q", got, want)
+	}
+	if _, err := strategy.Write([]byte("r")); err != os.ErrClosed {
+		t.Errorf("Write() after Close() error = %v, want %v", err, os.ErrClosed)
+	}
+	if err := strategy.Close(); err != os.ErrClosed {
+		t.Errorf("Close() twice error = %v, want %v", err, os.ErrClosed)
+	}
+}
+
+func TestWithFlushInterval(t *testing.T) {
+	tests := []struct {
+		name    string
+		options []Option
+	}{
+		{
+			options: []Option{WithBuffer(100), WithFlushInterval(10 * time.Millisecond)},
+		},
+		{
+			options: []Option{WithBuffer(100), WithSyncPolicy(SyncEvery(10 * time.Millisecond))},
+		},
+		{
+			options: []Option{WithBuffer(100), WithSyncPolicy(SyncEveryWrite)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filePath := fmt.Sprintf("/tmp/%s/error.log", util.RandString(10))
+			strategy := Get(filePath, tt.options...).(*Strategy)
+			defer strategy.Close()
+			if _, err := strategy.Write([]byte("Hello, Alog!")); err != nil {
+				t.Fatal(err)
+			}
+			for deadline := time.Now().Add(5 * time.Second); readTestFile(filePath) == ""; time.Sleep(time.Millisecond) {
+				if time.Now().After(deadline) {
+					t.Fatal("the buffer was not flushed")
+				}
+			}
+		})
+	}
+}
+
+func TestStrategy_Close(t *testing.T) {
+	filePath := fmt.Sprintf("/tmp/%s/error.log", util.RandString(10))
+	first, second := Get(filePath).(*Strategy), Get(filePath).(*Strategy)
+	if err := first.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if err := first.Close(); err != os.ErrClosed {
+		t.Errorf("Close() twice error = %v, want %v", err, os.ErrClosed)
+	}
+	if _, err := first.Write([]byte("Hello, Alog!")); err != os.ErrClosed {
+		t.Errorf("Write() after Close() error = %v, want %v", err, os.ErrClosed)
+	}
+	if _, err := second.Write([]byte("Hello, Alog!")); err != nil {
+		t.Errorf("Write() after closing the other strategy error = %v", err)
+	}
+	if err := second.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if err := (&Strategy{File: os.Stdout}).Close(); err != nil {
+		t.Errorf("Close() of the direct strategy error = %v", err)
+	}
+}
@@ -0,0 +1,14 @@
+////////////////////////////////////////////////////////////////////////////////
+// Author:   Nikita Koryabkin
+// Email:    Nikita@Koryabk.in
+// Telegram: https://t.me/Apologiz
+////////////////////////////////////////////////////////////////////////////////
+
+//go:build !linux && !darwin && !freebsd && !dragonfly
+
+package file
+
+// diskFreeSpace the disk guard stays inactive on this platform
+func diskFreeSpace(string) (uint64, error) {
+	return 0, errFreeSpaceUnsupported
+}
@@ -0,0 +1,22 @@
+////////////////////////////////////////////////////////////////////////////////
+// Author:   Nikita Koryabkin
+// Email:    Nikita@Koryabk.in
+// Telegram: https://t.me/Apologiz
+////////////////////////////////////////////////////////////////////////////////
+
+//go:build linux || darwin || freebsd || dragonfly
+
+package file
+
+import "syscall"
+
+// diskFreeSpace returns the space available to the process.
+// The disk guard and the lock share the build constraint of the platforms where syscall has
+// both Statfs and Flock, unix would also include solaris, aix and netbsd which lack them
+func diskFreeSpace(dir string) (uint64, error) {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(dir, &stat); err != nil {
+		return 0, err
+	}
+	return uint64(stat.Bavail) * uint64(stat.Bsize), nil
+}
@@ -0,0 +1,101 @@
+////////////////////////////////////////////////////////////////////////////////
+// Author:   Nikita Koryabkin
+// Email:    Nikita@Koryabk.in
+// Telegram: https://t.me/Apologiz
+////////////////////////////////////////////////////////////////////////////////
+
+package file
+
+import (
+	"errors"
+	"path/filepath"
+	"sync/atomic"
+	"time"
+
+	"github.com/mylockerteam/alog/entry"
+	"github.com/mylockerteam/alog/internal/diag"
+)
+
+// diskCheckInterval free space is checked at most once per the interval
+const diskCheckInterval = time.Second
+
+// diskGuard limits of the free space on the volume of the file
+type diskGuard struct {
+	minFree uint64
+	keep    []uint
+}
+
+var errFreeSpaceUnsupported = errors.New("free space is not supported on this platform")
+
+// freeSpace returns the bytes available on the volume of the directory, tests replace it
+var freeSpace = diskFreeSpace
+
+// WithDiskGuard stops writing when the free space on the volume falls below minFree bytes.
+// Entries of the kept logger types are still written, e.g. WithDiskGuard(100<<20, alog.Err),
+// without them all writes are paused. Dropped writes are counted, see Strategy.Dropped.
+// A notice is logged once when the guard degrades and once when the space recovers
+func WithDiskGuard(minFree uint64, keep ...uint) Option {
+	return func(o *options) {
+		o.diskGuard = &diskGuard{minFree: minFree, keep: keep}
+	}
+}
+
+// WriteEntry writes the entry, its logger type decides whether it is kept by the disk guard
+func (s *Strategy) WriteEntry(e *entry.Entry, p []byte) (n int, err error) {
+	if s.handle != nil && atomic.LoadUint32(&s.closed) == 0 {
+		return s.handle.write(p, e)
+	}
+	return s.Write(p)
+}
+
+// Dropped returns the number of writes dropped by the disk guard
+func (s *Strategy) Dropped() uint64 {
+	if s.handle == nil {
+		return 0
+	}
+	s.handle.mu.Lock()
+	defer s.handle.mu.Unlock()
+	return s.handle.dropped
+}
+
+// admit reports whether the write passes the disk guard,
+// writes without an entry are dropped while the space is low
+func (h *handle) admit(e *entry.Entry) bool {
+	g := h.options.diskGuard
+	if g == nil {
+		return true
+	}
+	if t := now(); t.Sub(h.spaceChecked) >= diskCheckInterval {
+		h.spaceChecked = t
+		h.checkSpace(g)
+	}
+	if !h.lowSpace || (e != nil && g.kept(e.Level)) {
+		return true
+	}
+	h.dropped++
+	return false
+}
+
+// checkSpace switches the guard, the state is kept when the free space is unknown
+func (h *handle) checkSpace(g *diskGuard) {
+	free, err := freeSpace(filepath.Dir(h.path))
+	if err != nil {
+		return
+	}
+	switch low := free < g.minFree; {
+	case low && !h.lowSpace:
+		diag.Printf("%s: %d bytes free, below %d, writes are dropped except logger types %v", h.path, free, g.minFree, g.keep)
+	case !low && h.lowSpace:
+		diag.Printf("%s: %d bytes free, writes are resumed, %d dropped", h.path, free, h.dropped)
+	}
+	h.lowSpace = free < g.minFree
+}
+
+func (g *diskGuard) kept(level uint) bool {
+	for _, keep := range g.keep {
+		if keep == level {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,101 @@
+////////////////////////////////////////////////////////////////////////////////
+// Author:   Nikita Koryabkin
+// Email:    Nikita@Koryabk.in
+// Telegram: https://t.me/Apologiz
+////////////////////////////////////////////////////////////////////////////////
+
+package file
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mylockerteam/alog/entry"
+	"github.com/mylockerteam/alog/internal/diag"
+	"github.com/mylockerteam/alog/util"
+)
+
+func TestWithDiskGuard(t *testing.T) {
+	const (
+		info = iota
+		warning
+		failure
+	)
+	defer func() { now, freeSpace = time.Now, diskFreeSpace }()
+	defer diag.SetOutput(os.Stderr)
+	current := time.Now()
+	now = func() time.Time { return current }
+	notices := &bytes.Buffer{}
+	diag.SetOutput(notices)
+	tests := []struct {
+		name        string
+		keep        []uint
+		want        string
+		wantDropped uint64
+	}{
+		{
+			keep:        []uint{failure},
+			want:        "info\nerror\nerror\ninfo\n",
+			wantDropped: 3,
+		},
+		{
+			want:        "info\ninfo\n",
+			wantDropped: 5,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			notices.Reset()
+			free := uint64(200)
+			freeSpace = func(string) (uint64, error) { return free, nil }
+			filePath := fmt.Sprintf("/tmp/%s/error.log", util.RandString(10))
+			s, err := New(fs, filePath, WithDiskGuard(100, tt.keep...))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer s.Close()
+			write := func(level uint, msg string) {
+				if _, err := s.WriteEntry(&entry.Entry{Level: level}, []byte(msg+"\n")); err != nil {
+					t.Errorf("WriteEntry() error = %v", err)
+				}
+			}
+			write(info, "info")
+			free, current = 50, current.Add(diskCheckInterval)
+			write(info, "info")
+			write(warning, "warning")
+			write(failure, "error")
+			write(failure, "error")
+			if _, err := s.Write([]byte("plain\n")); err != nil {
+				t.Errorf("Write() error = %v", err)
+			}
+			free, current = 200, current.Add(diskCheckInterval)
+			write(info, "info")
+			if got := readTestFile(filePath); got != tt.want {
+				t.Errorf("file = %q, want %q", got, tt.want)
+			}
+			if got := s.Dropped(); got != tt.wantDropped {
+				t.Errorf("Dropped() = %d, want %d", got, tt.wantDropped)
+			}
+			if got := strings.Count(notices.String(), "\n"); got != 2 {
+				t.Errorf("logged %d notices, want 2: %s", got, notices)
+			}
+		})
+	}
+}
+
+func TestWithDiskGuard_unknownSpace(t *testing.T) {
+	defer func() { freeSpace = diskFreeSpace }()
+	freeSpace = func(string) (uint64, error) { return 0, errFreeSpaceUnsupported }
+	s, err := New(fs, fmt.Sprintf("/tmp/%s/error.log", util.RandString(10)), WithDiskGuard(100))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	if _, err := s.Write([]byte("Hello, Alog!")); err != nil || s.Dropped() != 0 {
+		t.Errorf("Write() error = %v, dropped %d", err, s.Dropped())
+	}
+}
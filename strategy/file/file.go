@@ -8,68 +8,227 @@ package file
 
 import (
 	"errors"
-	"github.com/spf13/afero"
 	"io"
-	"log"
 	"os"
 	"path/filepath"
+	"reflect"
+	"sync/atomic"
+	"time"
+
+	"github.com/mylockerteam/alog/internal/diag"
+	"github.com/spf13/afero"
 )
 
 const (
-	fileOptions    = os.O_CREATE | os.O_APPEND | os.O_WRONLY
-	filePermission = 0755
+	fileOptions     = os.O_CREATE | os.O_APPEND | os.O_WRONLY
+	defaultFileMode = 0644
+	defaultDirMode  = 0755
 )
 
-// Strategy logging strategy in the File
+// Strategy logging strategy in the File.
+// Strategies created by Get write through the file shared by all strategies with the same path,
+// File is used when the strategy is created directly, e.g. &Strategy{File: os.Stdout}
 type Strategy struct {
-	_    io.Writer
-	File afero.File
+	_      io.Writer
+	File   afero.File
+	handle *handle
+	// closed the strategy released the handle, the file may still be used by others
+	closed uint32
+}
+
+// Option configures the file strategy
+type Option func(o *options)
+
+type options struct {
+	maxSize       int64
+	location      *time.Location
+	compress      bool
+	maxAge        time.Duration
+	maxBackups    int
+	maxTotalSize  int64
+	bufferSize    int
+	flushInterval time.Duration
+	syncPolicy    SyncPolicy
+	fileMode      os.FileMode
+	dirMode       os.FileMode
+	owner         *owner
+	fs            afero.Fs
+	diskGuard     *diskGuard
+	lock          bool
 }
 
 var errCanNotCreateDirectory = errors.New("can't create directory")
 var errFileNotDefined = errors.New("file is not defined")
+var errFsNotDefined = errors.New("filesystem is not defined")
+
+// fs is the filesystem of the strategies created by Get
 var fs = afero.NewOsFs()
 
-// Get File write strategy
-func Get(filePath string) io.Writer {
-	if addDirectory(filePath) == nil {
-		file, err := openFile(filePath)
-		if err == nil {
-			return &Strategy{
-				File: file,
-			}
-		}
+// now returns the current time, tests replace it
+var now = time.Now
+
+// Get File write strategy on the OS filesystem.
+// If the file can't be opened the error is logged and every write fails, use New to handle it
+func Get(filePath string, options ...Option) io.Writer {
+	s, err := New(fs, filePath, options...)
+	if err != nil {
+		diag.Println(err)
+		return &Strategy{}
+	}
+	return s
+}
+
+// New File write strategy on the filesystem, fails if the directory or the file can't be created.
+// Strategies for the same path and filesystem share the file and its rotation, they must have the same options.
+// The path may be a strftime template, e.g. /var/log/app/%Y-%m-%d/error.log, then a new file
+// is opened when the smallest unit of the template changes. See strftime for the directives
+func New(fs afero.Fs, filePath string, options ...Option) (*Strategy, error) {
+	if fs == nil {
+		return nil, errFsNotDefined
+	}
+	h, err := getHandle(fs, filePath, options)
+	if err != nil {
+		return nil, err
+	}
+	return &Strategy{handle: h}, nil
+}
+
+// WithMaxSize rotates the file when it would exceed the size in bytes.
+// The rotated file gets the time of rotation as a suffix, e.g. error-2006-01-02T15-04-05.000.log
+func WithMaxSize(size int64) Option {
+	return func(o *options) {
+		o.maxSize = size
+	}
+}
+
+// WithLocation sets the time zone in which path templates are evaluated, time.Local by default
+func WithLocation(location *time.Location) Option {
+	return func(o *options) {
+		o.location = location
+	}
+}
+
+// WithCompress compresses rotated files with gzip in the background
+func WithCompress() Option {
+	return func(o *options) {
+		o.compress = true
+	}
+}
+
+// WithMaxAge removes rotated files older than the age
+func WithMaxAge(age time.Duration) Option {
+	return func(o *options) {
+		o.maxAge = age
 	}
-	return &Strategy{}
+}
+
+// WithMaxBackups keeps at most the number of the newest rotated files
+func WithMaxBackups(count int) Option {
+	return func(o *options) {
+		o.maxBackups = count
+	}
+}
+
+// WithMaxTotalSize removes the oldest rotated files while the size of all files
+// including the active one exceeds the size in bytes
+func WithMaxTotalSize(size int64) Option {
+	return func(o *options) {
+		o.maxTotalSize = size
+	}
+}
+
+// equal reports whether the strategies with the options can share the file
+func (o *options) equal(other *options) bool {
+	a, b := *o, *other
+	if a.location.String() != b.location.String() {
+		return false
+	}
+	a.location, b.location = nil, nil
+	a.fs, b.fs = nil, nil
+	return reflect.DeepEqual(a, b)
+}
+
+// retention reports whether rotated files need the background cleanup
+func (o *options) retention() bool {
+	return o.compress || o.maxAge > 0 || o.maxBackups > 0 || o.maxTotalSize > 0
 }
 
 func (s *Strategy) Write(p []byte) (n int, err error) {
+	if s.handle != nil {
+		if atomic.LoadUint32(&s.closed) == 1 {
+			return 0, os.ErrClosed
+		}
+		return s.handle.write(p, nil)
+	}
 	if s.File != nil {
 		return s.File.Write(p)
 	}
 	return 0, errFileNotDefined
 }
 
-func addDirectory(filePath string) error {
+// newOptions returns the options with the defaults
+func newOptions(opts ...Option) *options {
+	o := &options{
+		location: time.Local,
+		fileMode: defaultFileMode,
+		dirMode:  defaultDirMode,
+		fs:       fs,
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+func addDirectory(filePath string, o *options) error {
 	if filePath == "" {
 		return errCanNotCreateDirectory
 	}
 	dir, _ := filepath.Split(filePath)
-	return createDirectoryIfNotExist(dir)
+	if dir == "" {
+		// the file is in the working directory
+		return nil
+	}
+	return createDirectoryIfNotExist(dir, o)
 }
 
-func createDirectoryIfNotExist(dirPath string) error {
-	_, err := fs.Stat(dirPath)
-	if os.IsNotExist(err) {
-		return fs.MkdirAll(dirPath, filePermission)
+// createDirectoryIfNotExist creates the directory with the missing parents,
+// all created directories get the mode and the owner of the options
+func createDirectoryIfNotExist(dirPath string, o *options) error {
+	_, err := o.fs.Stat(dirPath)
+	if !os.IsNotExist(err) {
+		return err
+	}
+	var created []string
+	for dir := filepath.Clean(dirPath); ; dir = filepath.Dir(dir) {
+		if _, err := o.fs.Stat(dir); !os.IsNotExist(err) {
+			break
+		}
+		created = append(created, dir)
+	}
+	if err := o.fs.MkdirAll(dirPath, o.dirMode); err != nil {
+		return err
+	}
+	for _, dir := range created {
+		if err := o.applyPermissions(dir, os.ModeDir|o.dirMode); err != nil {
+			return err
+		}
 	}
-	return err
+	return nil
 }
 
-func openFile(filePath string) (afero.File, error) {
+// openFile opens the file for appending, a new file gets the mode and the owner of the options
+func openFile(filePath string, o *options) (afero.File, error) {
 	if filePath == "" {
-		log.Println(afero.ErrFileNotFound)
 		return nil, afero.ErrFileNotFound
 	}
-	return fs.OpenFile(filePath, fileOptions, filePermission)
+	_, statErr := o.fs.Stat(filePath)
+	file, err := o.fs.OpenFile(filePath, fileOptions, o.fileMode)
+	if err == nil && os.IsNotExist(statErr) {
+		if err := o.applyPermissions(filePath, o.fileMode); err != nil {
+			file.Close()
+			return nil, err
+		}
+	}
+	return file, err
 }
@@ -7,11 +7,16 @@
 package file
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
 	"reflect"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/mylockerteam/alog/util"
 	"github.com/spf13/afero"
@@ -58,7 +63,7 @@ func Test_createDirectoryIfNotExist(t *testing.T) {
 	tests := casesCreateDirectoryIfNotExist()
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if err := createDirectoryIfNotExist(tt.args.dirPath); (err != nil) != tt.wantErr {
+			if err := createDirectoryIfNotExist(tt.args.dirPath, newOptions()); (err != nil) != tt.wantErr {
 				t.Errorf("createDirectoryIfNotExist() error = %v, wantErr %v", err, tt.wantErr)
 			}
 		})
@@ -115,7 +120,7 @@ func Test_openFile(t *testing.T) {
 	tests := casesOpenFile()
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := openFile(tt.args.filePath)
+			got, err := openFile(tt.args.filePath, newOptions())
 			if (err != nil) != tt.wantErr {
 				t.Errorf("openFile() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -164,7 +169,7 @@ func Test_addDirectory(t *testing.T) {
 	tests := casesAddDirectory()
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if err := addDirectory(tt.args.filePath); (err != nil) != tt.wantErr {
+			if err := addDirectory(tt.args.filePath, newOptions()); (err != nil) != tt.wantErr {
 				t.Errorf("addDirectory() error = %v, wantErr %v", err, tt.wantErr)
 			}
 		})
@@ -202,6 +207,145 @@ func TestGet(t *testing.T) {
 	}
 }
 
+func TestNew(t *testing.T) {
+	base := afero.NewMemMapFs()
+	if err := afero.WriteFile(base, "/var/log/app/error.log", []byte("old\n"), defaultFileMode); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name     string
+		fs       afero.Fs
+		filePath string
+		wantErr  bool
+	}{
+		{
+			fs:       afero.NewMemMapFs(),
+			filePath: "/tmp/app/error.log",
+			wantErr:  false,
+		},
+		{
+			fs:       afero.NewMemMapFs(),
+			filePath: "",
+			wantErr:  true,
+		},
+		{
+			fs:       nil,
+			filePath: "/tmp/app/error.log",
+			wantErr:  true,
+		},
+		{
+			fs:       afero.NewReadOnlyFs(base),
+			filePath: "/var/log/app/error.log",
+			wantErr:  true,
+		},
+		{
+			fs:       afero.NewCopyOnWriteFs(afero.NewReadOnlyFs(base), afero.NewMemMapFs()),
+			filePath: "/var/log/app/error.log",
+			wantErr:  false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := New(tt.fs, tt.filePath)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("New() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			defer got.Close()
+			if _, err := got.Write([]byte("new\n")); err != nil {
+				t.Errorf("Write() error = %v", err)
+			}
+			if data, _ := afero.ReadFile(tt.fs, tt.filePath); !strings.HasSuffix(string(data), "new\n") {
+				t.Errorf("file = %q, want the written line", data)
+			}
+		})
+	}
+	if data, _ := afero.ReadFile(base, "/var/log/app/error.log"); string(data) != "old\n" {
+		t.Errorf("base file = %q, want it unchanged", data)
+	}
+}
+
+func TestNew_relative(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+	s, err := New(afero.NewOsFs(), "app.log", WithMaxSize(10))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := s.Write([]byte("Hello, Alog!\n")); err != nil {
+			t.Errorf("Write() error = %v", err)
+		}
+	}
+	if err := s.Close(); err != nil {
+		t.Errorf("Close() error = %v", err)
+	}
+	if data, _ := os.ReadFile("app.log"); string(data) != "Hello, Alog!\n" {
+		t.Errorf("file = %q, want the written line", data)
+	}
+	if matches, _ := filepath.Glob("app-*.log"); len(matches) != 1 {
+		t.Errorf("rotated files = %v, want one", matches)
+	}
+}
+
+func TestNew_shared(t *testing.T) {
+	memFs := afero.NewMemMapFs()
+	filePath := fmt.Sprintf("/tmp/%s/error.log", util.RandString(10))
+	first, _ := New(memFs, filePath)
+	second, _ := New(memFs, filePath)
+	other, _ := New(afero.NewMemMapFs(), filePath)
+	if first.handle != second.handle || first.handle == other.handle {
+		t.Errorf("New() shares the file between different filesystems or not within one")
+	}
+	for _, option := range []Option{WithMaxSize(20), WithCompress(), WithLocation(time.UTC), WithFileMode(0600)} {
+		if _, err := New(memFs, filePath, option); !errors.Is(err, errOptionsConflict) {
+			t.Errorf("New() with other options error = %v, want %v", err, errOptionsConflict)
+		}
+	}
+	if _, err := New(memFs, filePath, WithLocation(time.Local)); err != nil {
+		t.Errorf("New() with the same options error = %v", err)
+	}
+}
+
+// taggedFs filesystem of a type which can't be a map key
+type taggedFs struct {
+	afero.Fs
+	tags map[string]string
+}
+
+func TestNew_notComparableFs(t *testing.T) {
+	memFs := taggedFs{Fs: afero.NewMemMapFs(), tags: map[string]string{}}
+	filePath := fmt.Sprintf("/tmp/%s/error.log", util.RandString(10))
+	first, err := New(memFs, filePath)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	second, err := New(memFs, filePath)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if first.handle == second.handle {
+		t.Errorf("New() shares the file of a filesystem without identity")
+	}
+	if _, err := first.Write([]byte("Hello, Alog!")); err != nil {
+		t.Errorf("Write() error = %v", err)
+	}
+	if err := first.Close(); err != nil {
+		t.Errorf("Close() error = %v", err)
+	}
+	if err := second.Close(); err != nil {
+		t.Errorf("Close() error = %v", err)
+	}
+}
+
 func TestWrite(t *testing.T) {
 	type args struct {
 		p []byte
@@ -237,3 +381,52 @@ func TestWrite(t *testing.T) {
 		})
 	}
 }
+
+func TestWithMaxSize(t *testing.T) {
+	dir := fmt.Sprintf("/tmp/%s/", util.RandString(10))
+	filePath := filepath.Join(dir, "error.log")
+	line := []byte("0123456789\n")
+	strategies := []io.Writer{Get(filePath, WithMaxSize(20)), Get(filePath, WithMaxSize(20))}
+	wg := sync.WaitGroup{}
+	for _, strategy := range strategies {
+		wg.Add(1)
+		go func(w io.Writer) {
+			defer wg.Done()
+			for i := 0; i < 10; i++ {
+				if _, err := w.Write(line); err != nil {
+					t.Errorf("Write() error = %v", err)
+				}
+			}
+		}(strategy)
+	}
+	wg.Wait()
+	files, err := afero.Glob(fs, filepath.Join(dir, "error*.log"))
+	if err != nil || len(files) != 20 {
+		t.Fatalf("rotation produced %d files, want 20, error = %v", len(files), err)
+	}
+	for _, name := range files {
+		if info, err := fs.Stat(name); err != nil || info.Size() != int64(len(line)) {
+			t.Errorf("file %s has wrong size, error = %v", name, err)
+		}
+	}
+}
+
+func TestWithLocation(t *testing.T) {
+	defer func() { now = time.Now }()
+	current := time.Date(2026, time.October, 18, 23, 30, 0, 0, time.UTC)
+	now = func() time.Time { return current }
+	location := time.FixedZone("UTC+1", 60*60)
+	dir := fmt.Sprintf("/tmp/%s", util.RandString(10))
+	strategy := Get(dir+"/%Y-%m-%d/error-%H.log", WithLocation(location))
+	for _, hour := range []time.Duration{0, time.Hour} {
+		current = current.Add(hour)
+		if _, err := strategy.Write([]byte("Hello, Alog!")); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+	}
+	for _, name := range []string{dir + "/2026-10-19/error-00.log", dir + "/2026-10-19/error-01.log"} {
+		if exists, _ := afero.Exists(fs, name); !exists {
+			t.Errorf("file %s was not created", name)
+		}
+	}
+}
@@ -0,0 +1,258 @@
+////////////////////////////////////////////////////////////////////////////////
+// Author:   Nikita Koryabkin
+// Email:    Nikita@Koryabk.in
+// Telegram: https://t.me/Apologiz
+////////////////////////////////////////////////////////////////////////////////
+
+package file
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mylockerteam/alog/entry"
+	"github.com/mylockerteam/alog/internal/diag"
+	"github.com/spf13/afero"
+)
+
+const backupTimeFormat = "2006-01-02T15-04-05.000"
+
+// handle file shared by the strategies with the same path.
+// All writes and rotations go under its mutex.
+// For a path template the path is evaluated at the rotation boundaries
+type handle struct {
+	mu       sync.Mutex
+	key      handleKey
+	shared   bool
+	template string
+	path     string
+	next     time.Time
+	options  *options
+	file     afero.File
+	size     int64
+	buffer   []byte
+	refs     int
+	closed   bool
+	// lowSpace the disk guard drops writes since the last check at spaceChecked
+	lowSpace     bool
+	spaceChecked time.Time
+	dropped      uint64
+	// done stops the flusher
+	done chan struct{}
+	// cleanups wakes up the cleaner of the rotated files
+	cleanups  chan struct{}
+	cleanupMu sync.Mutex
+}
+
+// handleKey identifies the shared file by the filesystem and the path
+type handleKey struct {
+	fs   afero.Fs
+	name string
+}
+
+var errOptionsConflict = errors.New("file is already open with other options")
+
+var handles = struct {
+	sync.Mutex
+	m map[handleKey]*handle
+}{m: map[handleKey]*handle{}}
+
+func getHandle(fs afero.Fs, filePath string, opts []Option) (*handle, error) {
+	handles.Lock()
+	defer handles.Unlock()
+	key, shared := newHandleKey(fs, filePath)
+	o := newOptions(opts...)
+	o.fs = fs
+	if h, ok := handles.m[key]; shared && ok {
+		if !h.options.equal(o) {
+			return nil, fmt.Errorf("%s: %w", filePath, errOptionsConflict)
+		}
+		h.mu.Lock()
+		h.refs++
+		h.mu.Unlock()
+		return h, nil
+	}
+	h := &handle{key: key, shared: shared, path: filePath, refs: 1, options: o}
+	if strings.ContainsRune(filePath, '%') {
+		h.template = filePath
+	}
+	if err := h.switchPath(now()); err != nil {
+		return nil, err
+	}
+	if h.options.retention() {
+		h.cleanups = make(chan struct{}, 1)
+		go h.cleaner()
+		h.scheduleCleanup()
+	}
+	if h.options.flushInterval > 0 || h.options.syncPolicy > 0 {
+		h.done = make(chan struct{})
+		go h.flusher()
+	}
+	if shared {
+		handles.m[key] = h
+	}
+	return h, nil
+}
+
+// newHandleKey returns the registry key, all OS filesystems are the same one.
+// A filesystem of a type which can't be a map key, e.g. a struct with a map, has no identity,
+// its files are not shared and are not reopened by ReopenAll
+func newHandleKey(fs afero.Fs, filePath string) (handleKey, bool) {
+	if _, ok := fs.(*afero.OsFs); ok {
+		fs = nil
+	}
+	if fs != nil && !reflect.TypeOf(fs).Comparable() {
+		return handleKey{name: filePath}, false
+	}
+	return handleKey{fs: fs, name: filePath}, true
+}
+
+func (h *handle) open() error {
+	if err := addDirectory(h.path, h.options); err != nil {
+		return err
+	}
+	file, err := openFile(h.path, h.options)
+	if err != nil {
+		return err
+	}
+	h.file, h.size = file, 0
+	if info, err := file.Stat(); err == nil {
+		h.size = info.Size()
+	}
+	return nil
+}
+
+// switchPath evaluates the template for the time and opens the resulting file
+func (h *handle) switchPath(t time.Time) error {
+	if h.template == "" {
+		return h.open()
+	}
+	if h.file != nil {
+		h.closeFile()
+		h.scheduleCleanup()
+	}
+	t = t.In(h.options.location)
+	h.path, h.next = strftime(h.template, t), nextBoundary(h.template, t)
+	return h.open()
+}
+
+// write writes the bytes of the entry, the entry is nil for plain writes
+func (h *handle) write(p []byte, e *entry.Entry) (n int, err error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed {
+		return 0, os.ErrClosed
+	}
+	if !h.admit(e) {
+		return len(p), nil
+	}
+	if !h.next.IsZero() {
+		if t := now(); !t.Before(h.next) {
+			if err := h.switchPath(t); err != nil {
+				return 0, err
+			}
+		}
+	}
+	if h.options.maxSize > 0 && h.size > 0 && h.size+int64(len(p)) > h.options.maxSize {
+		if err := h.rotate(); err != nil {
+			diag.Println(err)
+		}
+	}
+	if h.file == nil {
+		if err := h.open(); err != nil {
+			return 0, err
+		}
+	}
+	if h.options.bufferSize > 0 {
+		n, err = h.writeBuffered(p)
+	} else {
+		n, err = h.writeFile(p)
+	}
+	h.size += int64(n)
+	if err == nil && h.options.syncPolicy == SyncEveryWrite {
+		if err = h.flush(); err == nil {
+			err = h.file.Sync()
+		}
+	}
+	return n, err
+}
+
+// closeFile flushes the buffer and closes the active file.
+// With a sync policy the data is synced to the disk before closing
+func (h *handle) closeFile() {
+	if h.file == nil {
+		return
+	}
+	if err := h.flush(); err != nil {
+		diag.Println(err)
+	}
+	if h.options.syncPolicy != SyncNever {
+		if err := h.file.Sync(); err != nil {
+			diag.Println(err)
+		}
+	}
+	if err := h.file.Close(); err != nil {
+		diag.Println(err)
+	}
+	h.file = nil
+}
+
+// release drops a reference, the last one closes the file and stops the background work
+func (h *handle) release() error {
+	handles.Lock()
+	defer handles.Unlock()
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed {
+		return os.ErrClosed
+	}
+	if h.refs--; h.refs > 0 {
+		return nil
+	}
+	h.closed = true
+	if h.shared {
+		delete(handles.m, h.key)
+	}
+	h.closeFile()
+	if h.done != nil {
+		close(h.done)
+	}
+	if h.cleanups != nil {
+		close(h.cleanups)
+	}
+	return nil
+}
+
+// rotate renames the active file and opens a new one with the same path
+func (h *handle) rotate() error {
+	h.closeFile()
+	backup, err := backupName(h.path, h.options)
+	if err != nil {
+		return err
+	}
+	if err := h.options.fs.Rename(h.path, backup); err != nil {
+		return err
+	}
+	h.scheduleCleanup()
+	return h.open()
+}
+
+// backupName returns a free name for the rotated file, e.g. error-2006-01-02T15-04-05.000.log
+func backupName(filePath string, o *options) (string, error) {
+	ext := filepath.Ext(filePath)
+	prefix := fmt.Sprintf("%s-%s", filePath[:len(filePath)-len(ext)], now().Format(backupTimeFormat))
+	name := prefix + ext
+	for i := 1; ; i++ {
+		exists, err := afero.Exists(o.fs, name)
+		if err != nil || !exists {
+			return name, err
+		}
+		name = fmt.Sprintf("%s-%d%s", prefix, i, ext)
+	}
+}
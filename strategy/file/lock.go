@@ -0,0 +1,40 @@
+////////////////////////////////////////////////////////////////////////////////
+// Author:   Nikita Koryabkin
+// Email:    Nikita@Koryabk.in
+// Telegram: https://t.me/Apologiz
+////////////////////////////////////////////////////////////////////////////////
+
+package file
+
+// fder is implemented by files of the OS filesystem
+type fder interface {
+	Fd() uintptr
+}
+
+// WithLock takes an advisory lock on the file for every write, so records of several
+// processes writing to the same path never interleave. Every record is written with a single
+// write call, buffered records are written together under one lock.
+// Rotation is not coordinated between the processes, use it only in one of them
+func WithLock() Option {
+	return func(o *options) {
+		o.lock = true
+	}
+}
+
+// writeFile writes the bytes to the active file, under the file lock if it is configured.
+// The lock is skipped for files without a descriptor and on platforms without flock
+func (h *handle) writeFile(p []byte) (n int, err error) {
+	f, ok := h.file.(fder)
+	if !h.options.lock || !ok {
+		return h.file.Write(p)
+	}
+	if err := lockFile(f.Fd()); err != nil {
+		return 0, err
+	}
+	defer func() {
+		if unlockErr := unlockFile(f.Fd()); err == nil {
+			err = unlockErr
+		}
+	}()
+	return h.file.Write(p)
+}
@@ -0,0 +1,18 @@
+////////////////////////////////////////////////////////////////////////////////
+// Author:   Nikita Koryabkin
+// Email:    Nikita@Koryabk.in
+// Telegram: https://t.me/Apologiz
+////////////////////////////////////////////////////////////////////////////////
+
+//go:build !linux && !darwin && !freebsd && !dragonfly
+
+package file
+
+// lockFile there is no flock on this platform, records rely on the single append write
+func lockFile(uintptr) error {
+	return nil
+}
+
+func unlockFile(uintptr) error {
+	return nil
+}
@@ -0,0 +1,24 @@
+////////////////////////////////////////////////////////////////////////////////
+// Author:   Nikita Koryabkin
+// Email:    Nikita@Koryabk.in
+// Telegram: https://t.me/Apologiz
+////////////////////////////////////////////////////////////////////////////////
+
+//go:build linux || darwin || freebsd || dragonfly
+
+package file
+
+import "syscall"
+
+func lockFile(fd uintptr) error {
+	for {
+		// flock is interrupted by signals, e.g. the logrotate ones
+		if err := syscall.Flock(int(fd), syscall.LOCK_EX); err != syscall.EINTR {
+			return err
+		}
+	}
+}
+
+func unlockFile(fd uintptr) error {
+	return syscall.Flock(int(fd), syscall.LOCK_UN)
+}
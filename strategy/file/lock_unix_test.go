@@ -0,0 +1,148 @@
+////////////////////////////////////////////////////////////////////////////////
+// Author:   Nikita Koryabkin
+// Email:    Nikita@Koryabk.in
+// Telegram: https://t.me/Apologiz
+////////////////////////////////////////////////////////////////////////////////
+
+//go:build linux || darwin || freebsd || dragonfly
+
+package file
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/afero"
+)
+
+const (
+	lockTestEnv     = "ALOG_LOCK_TEST_FILE"
+	lockTestLine    = "Hello, Alog!\n"
+	lockTestDelay   = 200 * time.Millisecond
+	recordsTestEnv  = "ALOG_LOCK_TEST_RECORDS_FILE"
+	lockTestRecords = 50
+	lockTestSize    = 64 << 10
+)
+
+// TestWithLock_records starts the test binary as several writer processes,
+// their large records must not interleave. Local filesystems usually apply an append write
+// at once anyway, TestWithLock_processes shows that the writes wait for the lock
+func TestWithLock_records(t *testing.T) {
+	if filePath := os.Getenv(recordsTestEnv); filePath != "" {
+		writeLockTestRecords(t, filePath, os.Getenv(recordsTestEnv+"_CHAR"))
+		return
+	}
+	filePath := filepath.Join(t.TempDir(), "error.log")
+	var writers []*exec.Cmd
+	for i := 0; i < 4; i++ {
+		cmd := exec.Command(os.Args[0], "-test.run=^TestWithLock_records$")
+		cmd.Env = append(os.Environ(), recordsTestEnv+"="+filePath, recordsTestEnv+"_CHAR="+string(rune('a'+i)))
+		cmd.Stderr = os.Stderr
+		if err := cmd.Start(); err != nil {
+			t.Fatal(err)
+		}
+		writers = append(writers, cmd)
+	}
+	for _, cmd := range writers {
+		if err := cmd.Wait(); err != nil {
+			t.Fatalf("writer process error = %v", err)
+		}
+	}
+	file, err := os.Open(filePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, lockTestSize+1), lockTestSize+1)
+	lines := 0
+	for ; scanner.Scan(); lines++ {
+		line := scanner.Text()
+		if len(line) != lockTestSize || strings.Count(line, line[:1]) != len(line) {
+			t.Fatalf("line %d is interleaved", lines)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if want := len(writers) * lockTestRecords; lines != want {
+		t.Errorf("file has %d lines, want %d", lines, want)
+	}
+}
+
+func writeLockTestRecords(t *testing.T, filePath string, char string) {
+	s, err := New(afero.NewOsFs(), filePath, WithLock())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	record := []byte(strings.Repeat(char, lockTestSize) + "\n")
+	for i := 0; i < lockTestRecords; i++ {
+		if _, err := s.Write(record); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// TestWithLock_processes holds the lock while the test binary started as a writer process
+// tries to write, the write must wait until the lock is released
+func TestWithLock_processes(t *testing.T) {
+	if filePath := os.Getenv(lockTestEnv); filePath != "" {
+		writeLockTestRecord(t, filePath)
+		return
+	}
+	filePath := filepath.Join(t.TempDir(), "error.log")
+	file, err := os.OpenFile(filePath, fileOptions, defaultFileMode)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	if err := lockFile(file.Fd()); err != nil {
+		t.Fatal(err)
+	}
+	cmd := exec.Command(os.Args[0], "-test.run=^TestWithLock_processes$")
+	cmd.Env = append(os.Environ(), lockTestEnv+"="+filePath)
+	cmd.Stderr = os.Stderr
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := bufio.NewReader(stdout).ReadString('\n'); err != nil {
+		t.Fatalf("writer process didn't start, error = %v", err)
+	}
+	time.Sleep(lockTestDelay)
+	if info, err := os.Stat(filePath); err != nil || info.Size() != 0 {
+		t.Errorf("writer process wrote while the file was locked, error = %v", err)
+	}
+	if err := unlockFile(file.Fd()); err != nil {
+		t.Fatal(err)
+	}
+	if err := cmd.Wait(); err != nil {
+		t.Fatalf("writer process error = %v", err)
+	}
+	if data, err := os.ReadFile(filePath); err != nil || string(data) != lockTestLine {
+		t.Errorf("file contains %q, want %q, error = %v", data, lockTestLine, err)
+	}
+}
+
+func writeLockTestRecord(t *testing.T, filePath string) {
+	s, err := New(afero.NewOsFs(), filePath, WithLock())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	// the parent waits for the line before it checks that the write is blocked
+	fmt.Println("ready")
+	if _, err := s.Write([]byte(lockTestLine)); err != nil {
+		t.Fatal(err)
+	}
+}
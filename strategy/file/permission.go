@@ -0,0 +1,63 @@
+////////////////////////////////////////////////////////////////////////////////
+// Author:   Nikita Koryabkin
+// Email:    Nikita@Koryabk.in
+// Telegram: https://t.me/Apologiz
+////////////////////////////////////////////////////////////////////////////////
+
+package file
+
+import (
+	"os"
+
+	"github.com/spf13/afero"
+)
+
+// owner user and group of the created files and directories
+type owner struct {
+	uid int
+	gid int
+}
+
+// chowner is implemented by filesystems that can change the owner
+type chowner interface {
+	Chown(name string, uid, gid int) error
+}
+
+// WithFileMode sets the mode of created log files, rotated files and archives, 0644 by default
+func WithFileMode(mode os.FileMode) Option {
+	return func(o *options) {
+		o.fileMode = mode
+	}
+}
+
+// WithDirMode sets the mode of created directories, 0755 by default
+func WithDirMode(mode os.FileMode) Option {
+	return func(o *options) {
+		o.dirMode = mode
+	}
+}
+
+// WithOwner sets the user and group of created files and directories.
+// Pass -1 to keep one of them unchanged
+func WithOwner(uid, gid int) Option {
+	return func(o *options) {
+		o.owner = &owner{uid: uid, gid: gid}
+	}
+}
+
+// applyPermissions sets the mode regardless of umask and changes the owner if it is configured
+func (o *options) applyPermissions(name string, mode os.FileMode) error {
+	if err := o.fs.Chmod(name, mode); err != nil {
+		return err
+	}
+	if o.owner == nil {
+		return nil
+	}
+	switch c := o.fs.(type) {
+	case chowner:
+		return c.Chown(name, o.owner.uid, o.owner.gid)
+	case *afero.OsFs:
+		return os.Chown(name, o.owner.uid, o.owner.gid)
+	}
+	return nil
+}
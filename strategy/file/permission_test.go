@@ -0,0 +1,88 @@
+////////////////////////////////////////////////////////////////////////////////
+// Author:   Nikita Koryabkin
+// Email:    Nikita@Koryabk.in
+// Telegram: https://t.me/Apologiz
+////////////////////////////////////////////////////////////////////////////////
+
+package file
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/mylockerteam/alog/util"
+	"github.com/spf13/afero"
+)
+
+// chownFs records the changes of the owner
+type chownFs struct {
+	afero.Fs
+	chowned []string
+}
+
+func (c *chownFs) Chown(name string, uid, gid int) error {
+	c.chowned = append(c.chowned, fmt.Sprintf("%s:%d:%d", name, uid, gid))
+	return nil
+}
+
+func TestWithFileMode(t *testing.T) {
+	dir := fmt.Sprintf("/tmp/%s", util.RandString(10))
+	strategy := Get(dir+"/logs/error.log", WithFileMode(0600), WithDirMode(0700), WithMaxSize(1), WithCompress())
+	for i := 0; i < 2; i++ {
+		if _, err := strategy.Write([]byte("Hello, Alog!")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	strategy.(*Strategy).handle.cleanup()
+	archives := globTestFiles(t, dir+"/logs/error-*.log.gz")
+	if len(archives) != 1 {
+		t.Fatalf("rotation produced %v", archives)
+	}
+	tests := []struct {
+		name string
+		path string
+		want os.FileMode
+	}{
+		{
+			path: dir,
+			want: os.ModeDir | 0700,
+		},
+		{
+			path: dir + "/logs",
+			want: os.ModeDir | 0700,
+		},
+		{
+			path: dir + "/logs/error.log",
+			want: 0600,
+		},
+		{
+			path: archives[0],
+			want: 0600,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info, err := fs.Stat(tt.path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if info.Mode() != tt.want {
+				t.Errorf("%s mode = %v, want %v", tt.path, info.Mode(), tt.want)
+			}
+		})
+	}
+}
+
+func TestWithOwner(t *testing.T) {
+	c := &chownFs{Fs: afero.NewMemMapFs()}
+	dir := fmt.Sprintf("/tmp/%s", util.RandString(10))
+	if _, err := New(c, dir+"/error.log", WithOwner(1000, -1)); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{dir + ":1000:-1", "/tmp:1000:-1", dir + "/error.log:1000:-1"}
+	if !reflect.DeepEqual(c.chowned, want) {
+		t.Errorf("WithOwner() changed %v, want %v", c.chowned, want)
+	}
+}
@@ -0,0 +1,85 @@
+////////////////////////////////////////////////////////////////////////////////
+// Author:   Nikita Koryabkin
+// Email:    Nikita@Koryabk.in
+// Telegram: https://t.me/Apologiz
+////////////////////////////////////////////////////////////////////////////////
+
+package file
+
+import (
+	"os"
+	"os/signal"
+	"sync"
+
+	"github.com/mylockerteam/alog/internal/diag"
+)
+
+// Reopen closes the file and opens it again by the same path.
+// Use it after the file was renamed by an external tool such as logrotate
+func (s *Strategy) Reopen() error {
+	if s.handle == nil {
+		return nil
+	}
+	return s.handle.reopen()
+}
+
+// ReopenAll reopens the files of all strategies created by Get
+func ReopenAll() error {
+	handles.Lock()
+	list := make([]*handle, 0, len(handles.m))
+	for _, h := range handles.m {
+		list = append(list, h)
+	}
+	handles.Unlock()
+	var result error
+	for _, h := range list {
+		if err := h.reopen(); err != nil && result == nil {
+			result = err
+		}
+	}
+	return result
+}
+
+// NotifyReopen reopens all files when the process receives one of the signals,
+// SIGHUP and SIGUSR1 by default where the platform has them.
+// The returned function stops handling the signals
+func NotifyReopen(signals ...os.Signal) (stop func()) {
+	if len(signals) == 0 {
+		signals = reopenSignals
+	}
+	if len(signals) == 0 {
+		return func() {}
+	}
+	ch := make(chan os.Signal, 1)
+	done := make(chan struct{})
+	signal.Notify(ch, signals...)
+	go func() {
+		for {
+			select {
+			case <-ch:
+				if err := ReopenAll(); err != nil {
+					diag.Println(err)
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+	once := sync.Once{}
+	return func() {
+		once.Do(func() {
+			signal.Stop(ch)
+			close(done)
+		})
+	}
+}
+
+func (h *handle) reopen() error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed {
+		return os.ErrClosed
+	}
+	h.closeFile()
+	return h.open()
+}
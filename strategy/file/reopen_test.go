@@ -0,0 +1,63 @@
+////////////////////////////////////////////////////////////////////////////////
+// Author:   Nikita Koryabkin
+// Email:    Nikita@Koryabk.in
+// Telegram: https://t.me/Apologiz
+////////////////////////////////////////////////////////////////////////////////
+
+package file
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/mylockerteam/alog/util"
+	"github.com/spf13/afero"
+)
+
+func TestStrategy_Reopen(t *testing.T) {
+	filePath := fmt.Sprintf("/tmp/%s/error.log", util.RandString(10))
+	tests := []struct {
+		name     string
+		strategy *Strategy
+		reopen   func(s *Strategy) error
+	}{
+		{
+			strategy: Get(filePath).(*Strategy),
+			reopen: func(s *Strategy) error {
+				return s.Reopen()
+			},
+		},
+		{
+			strategy: Get(filePath).(*Strategy),
+			reopen: func(s *Strategy) error {
+				return ReopenAll()
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.strategy.Write([]byte("before")); err != nil {
+				t.Fatal(err)
+			}
+			if err := fs.Rename(filePath, filePath+".1"); err != nil {
+				t.Fatal(err)
+			}
+			if err := tt.reopen(tt.strategy); err != nil {
+				t.Fatalf("Reopen() error = %v", err)
+			}
+			if _, err := tt.strategy.Write([]byte("after")); err != nil {
+				t.Fatal(err)
+			}
+			if data, _ := afero.ReadFile(fs, filePath); string(data) != "after" {
+				t.Errorf("Reopen() file contains %q, want %q", data, "after")
+			}
+		})
+	}
+}
+
+func TestStrategy_ReopenWithoutHandle(t *testing.T) {
+	if err := (&Strategy{File: os.Stdout}).Reopen(); err != nil {
+		t.Errorf("Reopen() error = %v", err)
+	}
+}
@@ -0,0 +1,45 @@
+////////////////////////////////////////////////////////////////////////////////
+// Author:   Nikita Koryabkin
+// Email:    Nikita@Koryabk.in
+// Telegram: https://t.me/Apologiz
+////////////////////////////////////////////////////////////////////////////////
+
+//go:build unix
+
+package file
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/mylockerteam/alog/util"
+	"github.com/spf13/afero"
+)
+
+func TestNotifyReopen(t *testing.T) {
+	filePath := fmt.Sprintf("/tmp/%s/error.log", util.RandString(10))
+	strategy := Get(filePath)
+	stop := NotifyReopen()
+	defer stop()
+	if _, err := strategy.Write([]byte("before")); err != nil {
+		t.Fatal(err)
+	}
+	if err := fs.Rename(filePath, filePath+".1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := syscall.Kill(os.Getpid(), syscall.SIGHUP); err != nil {
+		t.Fatal(err)
+	}
+	for deadline := time.Now().Add(5 * time.Second); ; time.Sleep(10 * time.Millisecond) {
+		if exists, _ := afero.Exists(fs, filePath); exists {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("NotifyReopen() did not reopen the file")
+		}
+	}
+	stop()
+}
@@ -0,0 +1,282 @@
+////////////////////////////////////////////////////////////////////////////////
+// Author:   Nikita Koryabkin
+// Email:    Nikita@Koryabk.in
+// Telegram: https://t.me/Apologiz
+////////////////////////////////////////////////////////////////////////////////
+
+package file
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mylockerteam/alog/internal/diag"
+	"github.com/spf13/afero"
+)
+
+const (
+	compressedExt = ".gz"
+	// temporaryExt the archive is renamed from it only after it is completely written,
+	// so a crash never leaves a partial archive as the only copy
+	temporaryExt = ".tmp"
+	// backupTimePattern matches the time suffix of backupTimeFormat
+	backupTimePattern = `\d{4}-\d{2}-\d{2}T\d{2}-\d{2}-\d{2}\.\d{3}`
+)
+
+// directivePatterns match the values of the strftime directives
+var directivePatterns = map[byte]string{
+	'Y': `\d{4}`,
+	'y': `\d{2}`,
+	'm': `\d{2}`,
+	'b': `[A-Z][a-z]{2}`,
+	'd': `\d{2}`,
+	'j': `\d{3}`,
+	'H': `\d{2}`,
+	'M': `\d{2}`,
+	'S': `\d{2}`,
+	'%': `%`,
+}
+
+// backup rotated file
+type backup struct {
+	name string
+	info os.FileInfo
+}
+
+// scheduleCleanup wakes up the cleaner, requests made while it runs are coalesced
+func (h *handle) scheduleCleanup() {
+	if h.cleanups == nil {
+		return
+	}
+	select {
+	case h.cleanups <- struct{}{}:
+	default:
+	}
+}
+
+func (h *handle) cleaner() {
+	for range h.cleanups {
+		h.cleanup()
+	}
+}
+
+// cleanup compresses rotated files and removes those beyond the retention limits
+func (h *handle) cleanup() {
+	h.cleanupMu.Lock()
+	defer h.cleanupMu.Unlock()
+	h.mu.Lock()
+	active, size, patterns, match := h.path, h.size, h.backupPatterns(), h.backupMatcher()
+	h.mu.Unlock()
+
+	backups := listBackups(patterns, active, match, h.options)
+	if h.options.compress {
+		for i, name := range backups {
+			if !strings.HasSuffix(name, compressedExt) {
+				if err := compress(name, h.options); err != nil {
+					diag.Println(err)
+					continue
+				}
+				backups[i] = name + compressedExt
+			}
+		}
+	}
+	h.prune(backups, size)
+}
+
+// prune removes the oldest backups beyond the count, age and total size limits
+func (h *handle) prune(backups []string, total int64) {
+	infos := make([]backup, 0, len(backups))
+	for _, name := range backups {
+		if info, err := h.options.fs.Stat(name); err == nil {
+			infos = append(infos, backup{name: name, info: info})
+		}
+	}
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].info.ModTime().After(infos[j].info.ModTime())
+	})
+	for i, b := range infos {
+		total += b.info.Size()
+		if (h.options.maxBackups > 0 && i >= h.options.maxBackups) ||
+			(h.options.maxAge > 0 && now().Sub(b.info.ModTime()) > h.options.maxAge) ||
+			(h.options.maxTotalSize > 0 && total > h.options.maxTotalSize) {
+			removeFile(b.name, h.options)
+		}
+	}
+}
+
+// backupPatterns returns glob patterns of the rotated files.
+// Files rotated by size get a suffix before the extension, files of a path template
+// match the template with all directives replaced by *
+func (h *handle) backupPatterns() []string {
+	pattern := h.path
+	if h.template != "" {
+		pattern = templateGlob(h.template)
+	}
+	ext := filepath.Ext(pattern)
+	patterns := []string{pattern[:len(pattern)-len(ext)] + "-*" + ext}
+	if h.template != "" {
+		patterns = append(patterns, pattern)
+	}
+	return patterns
+}
+
+// backupMatcher returns the expression of the rotated file names, the glob patterns also match
+// sibling logs with the same prefix, e.g. error-payments.log for error.log.
+// The first group is the time of rotation, it is empty for files of previous periods of a path template
+func (h *handle) backupMatcher() *regexp.Regexp {
+	stem, ext := regexp.QuoteMeta(strings.TrimSuffix(h.path, filepath.Ext(h.path))), filepath.Ext(h.path)
+	if h.template != "" {
+		ext = filepath.Ext(h.template)
+		stem = templateRegexp(strings.TrimSuffix(h.template, ext))
+	}
+	expr := stem + "-(" + backupTimePattern + `)(?:-\d+)?` + regexp.QuoteMeta(ext)
+	if h.template != "" {
+		expr += "|" + templateRegexp(h.template)
+	}
+	suffix := "(?:" + regexp.QuoteMeta(compressedExt) + "(?:" + regexp.QuoteMeta(temporaryExt) + ")?)?"
+	return regexp.MustCompile("^(?:" + expr + ")" + suffix + "$")
+}
+
+// isBackup reports whether the name is a rotated file with a valid time of rotation
+func isBackup(name string, match *regexp.Regexp) bool {
+	m := match.FindStringSubmatch(name)
+	if m == nil {
+		return false
+	}
+	if m[1] == "" {
+		return true
+	}
+	_, err := time.Parse(backupTimeFormat, m[1])
+	return err == nil
+}
+
+// listBackups returns the rotated files, leftovers of an interrupted compression are removed
+func listBackups(patterns []string, active string, match *regexp.Regexp, o *options) []string {
+	seen := map[string]bool{active: true}
+	var backups []string
+	for _, pattern := range patterns {
+		for _, suffix := range []string{"", compressedExt, compressedExt + temporaryExt} {
+			matches, err := afero.Glob(o.fs, pattern+suffix)
+			if err != nil {
+				diag.Println(err)
+			}
+			for _, name := range matches {
+				if seen[name] || !isBackup(name, match) {
+					continue
+				}
+				seen[name] = true
+				switch {
+				case strings.HasSuffix(name, temporaryExt):
+					removeFile(name, o)
+				case !strings.HasSuffix(name, compressedExt) && fileExists(name+compressedExt, o):
+					// the archive is complete, only the original was not removed
+					removeFile(name, o)
+				default:
+					backups = append(backups, name)
+				}
+			}
+		}
+	}
+	return backups
+}
+
+// compress writes the gzip archive of the file and removes the original
+func compress(name string, o *options) error {
+	info, err := o.fs.Stat(name)
+	if err != nil {
+		return err
+	}
+	tmp := name + compressedExt + temporaryExt
+	if err := writeArchive(name, tmp, o); err != nil {
+		removeFile(tmp, o)
+		return err
+	}
+	if err := o.fs.Rename(tmp, name+compressedExt); err != nil {
+		return err
+	}
+	if err := o.fs.Chtimes(name+compressedExt, info.ModTime(), info.ModTime()); err != nil {
+		diag.Println(err)
+	}
+	return o.fs.Remove(name)
+}
+
+// writeArchive compresses the file into the archive and syncs it to the disk
+func writeArchive(name string, archive string, o *options) error {
+	src, err := o.fs.Open(name)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+	dst, err := o.fs.OpenFile(archive, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, o.fileMode)
+	if err != nil {
+		return err
+	}
+	if err := o.applyPermissions(archive, o.fileMode); err != nil {
+		dst.Close()
+		return err
+	}
+	gz := gzip.NewWriter(dst)
+	if _, err = io.Copy(gz, src); err == nil {
+		err = gz.Close()
+	}
+	if err == nil {
+		err = dst.Sync()
+	}
+	if closeErr := dst.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// templateRegexp returns the expression of the paths given by the template
+func templateRegexp(template string) string {
+	var b strings.Builder
+	for i := 0; i < len(template); i++ {
+		if template[i] == '%' && i+1 < len(template) {
+			i++
+			if pattern, ok := directivePatterns[template[i]]; ok {
+				b.WriteString(pattern)
+			} else {
+				b.WriteString(regexp.QuoteMeta(template[i-1 : i+1]))
+			}
+			continue
+		}
+		b.WriteString(regexp.QuoteMeta(template[i : i+1]))
+	}
+	return b.String()
+}
+
+// templateGlob replaces the directives of the path template with *
+func templateGlob(template string) string {
+	var b strings.Builder
+	for i := 0; i < len(template); i++ {
+		if template[i] == '%' && i+1 < len(template) {
+			i++
+			if template[i] == '%' {
+				b.WriteByte('%')
+			} else {
+				b.WriteByte('*')
+			}
+			continue
+		}
+		b.WriteByte(template[i])
+	}
+	return b.String()
+}
+
+func fileExists(name string, o *options) bool {
+	exists, _ := afero.Exists(o.fs, name)
+	return exists
+}
+
+func removeFile(name string, o *options) {
+	if err := o.fs.Remove(name); err != nil {
+		diag.Println(err)
+	}
+}
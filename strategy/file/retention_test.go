@@ -0,0 +1,273 @@
+////////////////////////////////////////////////////////////////////////////////
+// Author:   Nikita Koryabkin
+// Email:    Nikita@Koryabk.in
+// Telegram: https://t.me/Apologiz
+////////////////////////////////////////////////////////////////////////////////
+
+package file
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/mylockerteam/alog/util"
+	"github.com/spf13/afero"
+)
+
+func writeTestFile(t *testing.T, name string, content string, modTime time.Time) {
+	if err := afero.WriteFile(fs, name, []byte(content), defaultFileMode); err != nil {
+		t.Fatal(err)
+	}
+	if err := fs.Chtimes(name, modTime, modTime); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func globTestFiles(t *testing.T, pattern string) []string {
+	files, err := afero.Glob(fs, pattern)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Strings(files)
+	return files
+}
+
+func TestWithCompress(t *testing.T) {
+	dir := fmt.Sprintf("/tmp/%s", util.RandString(10))
+	strategy := Get(dir+"/error.log", WithMaxSize(5), WithCompress())
+	for _, line := range []string{"first", "second", "third"} {
+		if _, err := strategy.Write([]byte(line)); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+	}
+	strategy.(*Strategy).handle.cleanup()
+	archives := globTestFiles(t, dir+"/error-*.log.gz")
+	if len(archives) != 2 || len(globTestFiles(t, dir+"/error-*.log")) != 0 || len(globTestFiles(t, dir+"/*.tmp")) != 0 {
+		t.Fatalf("cleanup() left %v", globTestFiles(t, dir+"/*"))
+	}
+	var content []string
+	for _, name := range archives {
+		file, _ := fs.Open(name)
+		gz, err := gzip.NewReader(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, _ := ioutil.ReadAll(gz)
+		content = append(content, string(data))
+	}
+	sort.Strings(content)
+	if want := []string{"first", "second"}; !reflect.DeepEqual(content, want) {
+		t.Errorf("archives contain %v, want %v", content, want)
+	}
+}
+
+func TestHandle_cleanup(t *testing.T) {
+	modTime := time.Now()
+	tests := []struct {
+		name    string
+		options options
+		files   map[string]time.Duration
+		want    []string
+	}{
+		{
+			options: options{compress: true},
+			files: map[string]time.Duration{
+				"error-2026-10-19T10-00-01.000.log.gz.tmp": 0,
+				"error-2026-10-19T10-00-02.000.log":        0,
+				"error-2026-10-19T10-00-02.000.log.gz":     0,
+			},
+			want: []string{"error-2026-10-19T10-00-02.000.log.gz", "error.log"},
+		},
+		{
+			options: options{maxBackups: 2},
+			files: map[string]time.Duration{
+				"error-2026-10-19T10-00-01.000.log":    3 * time.Hour,
+				"error-2026-10-19T10-00-02.000.log.gz": 2 * time.Hour,
+				"error-2026-10-19T10-00-03.000-1.log":  time.Hour,
+				"errors.log":                           4 * time.Hour,
+			},
+			want: []string{"error-2026-10-19T10-00-02.000.log.gz", "error-2026-10-19T10-00-03.000-1.log", "error.log", "errors.log"},
+		},
+		{
+			options: options{maxAge: 90 * time.Minute},
+			files: map[string]time.Duration{
+				"error-2026-10-19T10-00-01.000.log": 2 * time.Hour,
+				"error-2026-10-19T10-00-02.000.log": time.Hour,
+			},
+			want: []string{"error-2026-10-19T10-00-02.000.log", "error.log"},
+		},
+		{
+			options: options{maxTotalSize: 12},
+			files: map[string]time.Duration{
+				"error-2026-10-19T10-00-01.000.log": 2 * time.Hour,
+				"error-2026-10-19T10-00-02.000.log": time.Hour,
+			},
+			want: []string{"error-2026-10-19T10-00-02.000.log", "error.log"},
+		},
+		{
+			name:    "siblings",
+			options: options{compress: true, maxBackups: 1},
+			files: map[string]time.Duration{
+				"error-2026-10-19T10-00-01.000.log": 2 * time.Hour,
+				"error-2026-10-19T10-00-02.000.log": time.Hour,
+				"error-payments.log":                3 * time.Hour,
+				"error-2026-13-45T99-00-00.000.log": 3 * time.Hour,
+			},
+			want: []string{"error-2026-10-19T10-00-02.000.log.gz", "error-2026-13-45T99-00-00.000.log", "error-payments.log", "error.log"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := fmt.Sprintf("/tmp/%s", util.RandString(10))
+			writeTestFile(t, dir+"/error.log", "active", modTime)
+			for name, age := range tt.files {
+				writeTestFile(t, dir+"/"+name, "backup", modTime.Add(-age))
+			}
+			tt.options.fs = fs
+			h := &handle{path: dir + "/error.log", size: 6, options: &tt.options}
+			h.cleanup()
+			var got []string
+			for _, name := range globTestFiles(t, dir+"/*") {
+				got = append(got, filepath.Base(name))
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("cleanup() left %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHandle_cleanupTemplate(t *testing.T) {
+	dir := fmt.Sprintf("/tmp/%s", util.RandString(10))
+	modTime := time.Now()
+	writeTestFile(t, dir+"/2026-10-17/error.log", "old", modTime.Add(-48*time.Hour))
+	writeTestFile(t, dir+"/2026-10-18/error.log", "old", modTime.Add(-24*time.Hour))
+	writeTestFile(t, dir+"/2026-10-19/error.log", "active", modTime)
+	writeTestFile(t, dir+"/2026-10-19/error-payments.log", "sibling", modTime.Add(-72*time.Hour))
+	writeTestFile(t, dir+"/archive/error.log", "sibling", modTime.Add(-72*time.Hour))
+	h := &handle{
+		template: dir + "/%Y-%m-%d/error.log",
+		path:     dir + "/2026-10-19/error.log",
+		options:  &options{maxBackups: 1, fs: fs},
+	}
+	h.cleanup()
+	want := []string{
+		dir + "/2026-10-18/error.log",
+		dir + "/2026-10-19/error-payments.log",
+		dir + "/2026-10-19/error.log",
+		dir + "/archive/error.log",
+	}
+	if got := globTestFiles(t, dir+"/*/*"); !reflect.DeepEqual(got, want) {
+		t.Errorf("cleanup() left %v, want %v", got, want)
+	}
+}
+
+func Test_templateGlob(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		want     string
+	}{
+		{
+			template: "/var/log/%Y-%m-%d/error-%H.log",
+			want:     "/var/log/*-*-*/error-*.log",
+		},
+		{
+			template: "100%%-%Y%",
+			want:     "100%-*%",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := templateGlob(tt.template); got != tt.want {
+				t.Errorf("templateGlob() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHandle_backupMatcher(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		path     string
+		file     string
+		want     bool
+	}{
+		{
+			path: "/var/log/error.log",
+			file: "/var/log/error-2026-10-19T10-00-00.000.log",
+			want: true,
+		},
+		{
+			path: "/var/log/error.log",
+			file: "/var/log/error-2026-10-19T10-00-00.000-2.log.gz",
+			want: true,
+		},
+		{
+			path: "/var/log/error.log",
+			file: "/var/log/error-payments.log",
+		},
+		{
+			path: "/var/log/error.log",
+			file: "/var/log/error-2026-10-19T10-00-00.000-payments.log",
+		},
+		{
+			path: "/var/log/error.log",
+			file: "/var/log/error-2026-19-10T10-00-00.000.log",
+		},
+		{
+			template: "/var/log/%Y-%m-%d/error.log",
+			path:     "/var/log/2026-10-19/error.log",
+			file:     "/var/log/2026-10-18/error.log.gz",
+			want:     true,
+		},
+		{
+			template: "/var/log/%Y-%m-%d/error.log",
+			path:     "/var/log/2026-10-19/error.log",
+			file:     "/var/log/2026-10-18/error-2026-10-18T23-59-59.999.log",
+			want:     true,
+		},
+		{
+			template: "/var/log/%Y-%m-%d/error.log",
+			path:     "/var/log/2026-10-19/error.log",
+			file:     "/var/log/2026-10-18/error-payments.log",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := &handle{template: tt.template, path: tt.path}
+			if got := isBackup(tt.file, h.backupMatcher()); got != tt.want {
+				t.Errorf("isBackup(%v) = %v, want %v", tt.file, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHandle_cleanerStops(t *testing.T) {
+	s, err := New(afero.NewMemMapFs(), "/log/error.log", WithMaxBackups(1))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	h := s.handle
+	done := make(chan struct{})
+	go func() {
+		// a second cleaner on the same channel, it ends only when the channel is closed
+		h.cleaner()
+		close(done)
+	}()
+	if err := h.release(); err != nil {
+		t.Fatalf("release() error = %v", err)
+	}
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Errorf("cleaner() didn't stop after release()")
+	}
+}
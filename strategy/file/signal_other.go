@@ -0,0 +1,14 @@
+////////////////////////////////////////////////////////////////////////////////
+// Author:   Nikita Koryabkin
+// Email:    Nikita@Koryabk.in
+// Telegram: https://t.me/Apologiz
+////////////////////////////////////////////////////////////////////////////////
+
+//go:build !unix
+
+package file
+
+import "os"
+
+// reopenSignals there are no logrotate signals on this platform
+var reopenSignals []os.Signal
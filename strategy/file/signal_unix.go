@@ -0,0 +1,16 @@
+////////////////////////////////////////////////////////////////////////////////
+// Author:   Nikita Koryabkin
+// Email:    Nikita@Koryabk.in
+// Telegram: https://t.me/Apologiz
+////////////////////////////////////////////////////////////////////////////////
+
+//go:build unix
+
+package file
+
+import (
+	"os"
+	"syscall"
+)
+
+var reopenSignals = []os.Signal{syscall.SIGHUP, syscall.SIGUSR1}
@@ -0,0 +1,112 @@
+////////////////////////////////////////////////////////////////////////////////
+// Author:   Nikita Koryabkin
+// Email:    Nikita@Koryabk.in
+// Telegram: https://t.me/Apologiz
+////////////////////////////////////////////////////////////////////////////////
+
+package file
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Units of the path template, from the largest to the smallest
+const (
+	unitNone = iota
+	unitYear
+	unitMonth
+	unitDay
+	unitHour
+	unitMinute
+	unitSecond
+)
+
+var directiveUnit = map[byte]int{
+	'Y': unitYear,
+	'y': unitYear,
+	'm': unitMonth,
+	'b': unitMonth,
+	'd': unitDay,
+	'j': unitDay,
+	'H': unitHour,
+	'M': unitMinute,
+	'S': unitSecond,
+}
+
+// strftime formats the time by the template.
+// Supported directives: %Y, %y, %m, %b, %d, %j, %H, %M, %S and %%, others are kept as is
+func strftime(template string, t time.Time) string {
+	var b strings.Builder
+	for i := 0; i < len(template); i++ {
+		if template[i] != '%' || i+1 == len(template) {
+			b.WriteByte(template[i])
+			continue
+		}
+		i++
+		switch template[i] {
+		case 'Y':
+			fmt.Fprintf(&b, "%04d", t.Year())
+		case 'y':
+			fmt.Fprintf(&b, "%02d", t.Year()%100)
+		case 'm':
+			fmt.Fprintf(&b, "%02d", int(t.Month()))
+		case 'b':
+			b.WriteString(t.Month().String()[:3])
+		case 'd':
+			fmt.Fprintf(&b, "%02d", t.Day())
+		case 'j':
+			fmt.Fprintf(&b, "%03d", t.YearDay())
+		case 'H':
+			fmt.Fprintf(&b, "%02d", t.Hour())
+		case 'M':
+			fmt.Fprintf(&b, "%02d", t.Minute())
+		case 'S':
+			fmt.Fprintf(&b, "%02d", t.Second())
+		case '%':
+			b.WriteByte('%')
+		default:
+			b.WriteByte('%')
+			b.WriteByte(template[i])
+		}
+	}
+	return b.String()
+}
+
+// templateUnit returns the smallest unit used in the template
+func templateUnit(template string) int {
+	unit := unitNone
+	for i := 0; i+1 < len(template); i++ {
+		if template[i] != '%' {
+			continue
+		}
+		i++
+		if u := directiveUnit[template[i]]; u > unit {
+			unit = u
+		}
+	}
+	return unit
+}
+
+// nextBoundary returns the time when the template gives the next path.
+// The zero time means that the path never changes
+func nextBoundary(template string, t time.Time) time.Time {
+	year, month, day := t.Date()
+	hour, minute, sec := t.Clock()
+	switch templateUnit(template) {
+	case unitYear:
+		return time.Date(year+1, time.January, 1, 0, 0, 0, 0, t.Location())
+	case unitMonth:
+		return time.Date(year, month+1, 1, 0, 0, 0, 0, t.Location())
+	case unitDay:
+		return time.Date(year, month, day+1, 0, 0, 0, 0, t.Location())
+	case unitHour:
+		return time.Date(year, month, day, hour+1, 0, 0, 0, t.Location())
+	case unitMinute:
+		return time.Date(year, month, day, hour, minute+1, 0, 0, t.Location())
+	case unitSecond:
+		return time.Date(year, month, day, hour, minute, sec+1, 0, t.Location())
+	}
+	return time.Time{}
+}
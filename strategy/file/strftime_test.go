@@ -0,0 +1,86 @@
+////////////////////////////////////////////////////////////////////////////////
+// Author:   Nikita Koryabkin
+// Email:    Nikita@Koryabk.in
+// Telegram: https://t.me/Apologiz
+////////////////////////////////////////////////////////////////////////////////
+
+package file
+
+import (
+	"testing"
+	"time"
+)
+
+var testTime = time.Date(2026, time.October, 18, 9, 5, 7, 0, time.UTC)
+
+func Test_strftime(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		want     string
+	}{
+		{
+			template: "/var/log/app/%Y-%m-%d/error.log",
+			want:     "/var/log/app/2026-10-18/error.log",
+		},
+		{
+			template: "error-%Y-%m-%dT%H.log",
+			want:     "error-2026-10-18T09.log",
+		},
+		{
+			template: "%y %b %j %M %S %% %q %",
+			want:     "26 Oct 291 05 07 % %q %",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := strftime(tt.template, testTime); got != tt.want {
+				t.Errorf("strftime() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_nextBoundary(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		want     time.Time
+	}{
+		{
+			template: "%Y.log",
+			want:     time.Date(2027, time.January, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			template: "%Y-%m.log",
+			want:     time.Date(2026, time.November, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			template: "%Y/%j.log",
+			want:     time.Date(2026, time.October, 19, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			template: "%H/%Y-%m-%d.log",
+			want:     time.Date(2026, time.October, 18, 10, 0, 0, 0, time.UTC),
+		},
+		{
+			template: "%M.log",
+			want:     time.Date(2026, time.October, 18, 9, 6, 0, 0, time.UTC),
+		},
+		{
+			template: "%S.log",
+			want:     time.Date(2026, time.October, 18, 9, 5, 8, 0, time.UTC),
+		},
+		{
+			template: "100%%.log",
+			want:     time.Time{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := nextBoundary(tt.template, testTime); !got.Equal(tt.want) {
+				t.Errorf("nextBoundary() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+////////////////////////////////////////////////////////////////////////////////
+// Author:   Nikita Koryabkin
+// Email:    Nikita@Koryabk.in
+// Telegram: https://t.me/Apologiz
+////////////////////////////////////////////////////////////////////////////////
+
+package jsonl
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mylockerteam/alog/entry"
+)
+
+// Keys of the entry in the JSON object, fields with the same keys are prefixed with "fields."
+const (
+	TimeKey    = "time"
+	LevelKey   = "level"
+	CallerKey  = "caller"
+	MessageKey = "msg"
+	StackKey   = "stack"
+)
+
+// Strategy writes every entry as a JSON object on its own line
+type Strategy struct {
+	_   io.Writer
+	mu  sync.Mutex
+	out io.Writer
+}
+
+// Get JSON lines write strategy
+func Get(out io.Writer) io.Writer {
+	return &Strategy{out: out}
+}
+
+// Write writes the formatted line as the message of an object
+func (s *Strategy) Write(p []byte) (n int, err error) {
+	return s.WriteEntry(&entry.Entry{Message: strings.TrimRight(string(p), "\n")}, p)
+}
+
+// WriteEntry writes the entry as an object, values of the fields which can't be encoded are formatted
+func (s *Strategy) WriteEntry(e *entry.Entry, p []byte) (n int, err error) {
+	var b bytes.Buffer
+	b.WriteByte('{')
+	if !e.Time.IsZero() {
+		writeKey(&b, TimeKey, e.Time.Format(time.RFC3339Nano))
+	}
+	if e.LevelName != "" {
+		writeKey(&b, LevelKey, strings.ToLower(e.LevelName))
+	}
+	if e.Caller.Defined() {
+		writeKey(&b, CallerKey, e.Caller.String())
+	}
+	writeKey(&b, MessageKey, e.Message)
+	for _, field := range e.Fields {
+		key := field.Key
+		switch key {
+		case TimeKey, LevelKey, CallerKey, MessageKey, StackKey:
+			key = "fields." + key
+		}
+		writeKey(&b, key, field.Value)
+	}
+	if e.Stack != "" {
+		writeKey(&b, StackKey, e.Stack)
+	}
+	b.WriteString("}\n")
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, err := s.out.Write(b.Bytes()); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+func writeKey(b *bytes.Buffer, key string, value interface{}) {
+	if b.Len() > 1 {
+		b.WriteByte(',')
+	}
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		encoded, _ = json.Marshal(fmt.Sprint(value))
+	}
+	name, _ := json.Marshal(key)
+	b.Write(name)
+	b.WriteByte(':')
+	b.Write(encoded)
+}
@@ -0,0 +1,74 @@
+////////////////////////////////////////////////////////////////////////////////
+// Author:   Nikita Koryabkin
+// Email:    Nikita@Koryabk.in
+// Telegram: https://t.me/Apologiz
+////////////////////////////////////////////////////////////////////////////////
+
+package jsonl
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/mylockerteam/alog/entry"
+)
+
+func TestStrategy_WriteEntry(t *testing.T) {
+	tests := []struct {
+		name  string
+		entry *entry.Entry
+		want  string
+	}{
+		{
+			entry: &entry.Entry{
+				LevelName: "Error",
+				Time:      time.Date(2026, time.October, 19, 12, 30, 0, 0, time.UTC),
+				Caller:    entry.Caller{File: "main.go", Line: 7},
+				Message:   "refused",
+				Fields: []entry.Field{
+					{Key: "tenant", Value: "acme"},
+					{Key: "attempt", Value: 3},
+					{Key: "msg", Value: "shadowed"},
+					{Key: "callback", Value: func() {}},
+				},
+				Stack: "goroutine 1",
+			},
+			want: `{"time":"2026-10-19T12:30:00Z","level":"error","caller":"main.go:7","msg":"refused",` +
+				`"tenant":"acme","attempt":3,"fields.msg":"shadowed","callback":"` + "\x00" + `","stack":"goroutine 1"}` + "\n",
+		},
+		{
+			entry: &entry.Entry{Message: "plain"},
+			want:  `{"msg":"plain"}` + "\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := &bytes.Buffer{}
+			if n, err := Get(out).(*Strategy).WriteEntry(tt.entry, []byte("line")); err != nil || n != 4 {
+				t.Fatalf("WriteEntry() = %d, error = %v", n, err)
+			}
+			got := out.String()
+			if tt.entry.Fields != nil {
+				// the formatted function is an address
+				start := bytes.Index(out.Bytes(), []byte(`"callback":"`)) + len(`"callback":"`)
+				end := start + bytes.IndexByte(out.Bytes()[start:], '"')
+				got = got[:start] + "\x00" + got[end:]
+			}
+			if got != tt.want {
+				t.Errorf("WriteEntry() wrote\n%s, want\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStrategy_Write(t *testing.T) {
+	out := &bytes.Buffer{}
+	p := []byte("[Info] 2026-10-19;\"quoted\"\n")
+	if n, err := Get(out).Write(p); err != nil || n != len(p) {
+		t.Fatalf("Write() = %d, error = %v", n, err)
+	}
+	if want := `{"msg":"[Info] 2026-10-19;\"quoted\""}` + "\n"; out.String() != want {
+		t.Errorf("Write() wrote %s, want %s", out.String(), want)
+	}
+}
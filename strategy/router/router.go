@@ -0,0 +1,271 @@
+////////////////////////////////////////////////////////////////////////////////
+// Author:   Nikita Koryabkin
+// Email:    Nikita@Koryabk.in
+// Telegram: https://t.me/Apologiz
+////////////////////////////////////////////////////////////////////////////////
+
+package router
+
+import (
+	"container/list"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mylockerteam/alog/entry"
+	"github.com/mylockerteam/alog/internal/diag"
+	"github.com/mylockerteam/alog/strategy/file"
+	"github.com/spf13/afero"
+)
+
+const (
+	defaultMaxOpen = 64
+	defaultValue   = "unknown"
+)
+
+var errUnclosedPlaceholder = errors.New("unclosed placeholder in the path template")
+var errNoPlaceholder = errors.New("path template has no placeholders")
+
+// Strategy writes every entry to the file whose path is resolved from the template by the entry fields,
+// e.g. /var/log/app/tenants/{tenant}.log. At most maxOpen files are kept open,
+// the least recently used one is closed when another is needed
+type Strategy struct {
+	_           io.Writer
+	fs          afero.Fs
+	segments    []segment
+	options     []file.Option
+	maxOpen     int
+	idleTimeout time.Duration
+	value       string
+
+	mu    sync.Mutex
+	lru   *list.List
+	files map[string]*list.Element
+	done  chan struct{}
+}
+
+// Option configures the router
+type Option func(s *Strategy)
+
+// segment part of the path template, either a literal or a field placeholder
+type segment struct {
+	text  string
+	field bool
+}
+
+// openFile element of the LRU list
+type openFile struct {
+	path     string
+	strategy *file.Strategy
+	used     time.Time
+}
+
+// now returns the current time, tests replace it
+var now = time.Now
+
+// New router strategy for the path template with {field} placeholders.
+// The resolved path may still contain strftime directives, see file.Get
+func New(template string, options ...Option) (*Strategy, error) {
+	segments, err := parseTemplate(template)
+	if err != nil {
+		return nil, err
+	}
+	s := &Strategy{
+		fs:       afero.NewOsFs(),
+		segments: segments,
+		maxOpen:  defaultMaxOpen,
+		value:    defaultValue,
+		lru:      list.New(),
+		files:    map[string]*list.Element{},
+	}
+	for _, option := range options {
+		option(s)
+	}
+	if s.idleTimeout > 0 {
+		s.done = make(chan struct{})
+		go s.janitor(s.done)
+	}
+	return s, nil
+}
+
+// WithFs sets the filesystem of the files, the OS one by default
+func WithFs(fs afero.Fs) Option {
+	return func(s *Strategy) {
+		s.fs = fs
+	}
+}
+
+// WithFileOptions sets the options of every opened file
+func WithFileOptions(options ...file.Option) Option {
+	return func(s *Strategy) {
+		s.options = options
+	}
+}
+
+// WithMaxOpen limits the number of open files, 64 by default
+func WithMaxOpen(count int) Option {
+	return func(s *Strategy) {
+		if count > 0 {
+			s.maxOpen = count
+		}
+	}
+}
+
+// WithIdleTimeout closes files which were not written for the duration
+func WithIdleTimeout(timeout time.Duration) Option {
+	return func(s *Strategy) {
+		s.idleTimeout = timeout
+	}
+}
+
+// WithDefault sets the value of placeholders whose field is missing or can't be used in a path, "unknown" by default
+func WithDefault(value string) Option {
+	return func(s *Strategy) {
+		s.value = value
+	}
+}
+
+// Write writes the message without fields to the path with the default values
+func (s *Strategy) Write(p []byte) (n int, err error) {
+	return s.WriteEntry(&entry.Entry{}, p)
+}
+
+// WriteEntry writes the message to the file resolved by the fields of the entry
+func (s *Strategy) WriteEntry(e *entry.Entry, p []byte) (n int, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	f, err := s.file(s.resolve(e.Fields))
+	if err != nil {
+		return 0, err
+	}
+	return f.WriteEntry(e, p)
+}
+
+// Close closes all open files and stops closing the idle ones
+func (s *Strategy) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.done != nil {
+		close(s.done)
+		s.done = nil
+	}
+	var err error
+	for s.lru.Len() > 0 {
+		if closeErr := s.evict(s.lru.Back()); err == nil {
+			err = closeErr
+		}
+	}
+	return err
+}
+
+// file returns the open file of the path and marks it as recently used
+func (s *Strategy) file(path string) (*file.Strategy, error) {
+	if element, ok := s.files[path]; ok {
+		element.Value.(*openFile).used = now()
+		s.lru.MoveToFront(element)
+		return element.Value.(*openFile).strategy, nil
+	}
+	for s.lru.Len() >= s.maxOpen {
+		if err := s.evict(s.lru.Back()); err != nil {
+			diag.Println(err)
+		}
+	}
+	strategy, err := file.New(s.fs, path, s.options...)
+	if err != nil {
+		return nil, err
+	}
+	s.files[path] = s.lru.PushFront(&openFile{path: path, strategy: strategy, used: now()})
+	return strategy, nil
+}
+
+func (s *Strategy) evict(element *list.Element) error {
+	f := s.lru.Remove(element).(*openFile)
+	delete(s.files, f.path)
+	return f.strategy.Close()
+}
+
+// closeIdle closes the files which were not written since the time
+func (s *Strategy) closeIdle(since time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for element := s.lru.Back(); element != nil && element.Value.(*openFile).used.Before(since); element = s.lru.Back() {
+		if err := s.evict(element); err != nil {
+			diag.Println(err)
+		}
+	}
+}
+
+func (s *Strategy) janitor(done <-chan struct{}) {
+	ticker := time.NewTicker(s.idleTimeout / 2)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			s.closeIdle(now().Add(-s.idleTimeout))
+		case <-done:
+			return
+		}
+	}
+}
+
+// resolve replaces the placeholders of the template with the field values
+func (s *Strategy) resolve(fields []entry.Field) string {
+	var b strings.Builder
+	for _, seg := range s.segments {
+		if !seg.field {
+			b.WriteString(seg.text)
+			continue
+		}
+		value := s.value
+		for _, field := range fields {
+			if field.Key == seg.text {
+				value = sanitize(fmt.Sprint(field.Value), s.value)
+			}
+		}
+		b.WriteString(value)
+	}
+	return b.String()
+}
+
+// sanitize keeps the value within one path element, other characters are replaced with _
+func sanitize(value string, fallback string) string {
+	b := []byte(value)
+	for i, c := range b {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-' || c == '_' || c == '.') {
+			b[i] = '_'
+		}
+	}
+	if value = string(b); value == "" || value == "." || value == ".." {
+		return fallback
+	}
+	return value
+}
+
+func parseTemplate(template string) ([]segment, error) {
+	var segments []segment
+	fields := 0
+	for template != "" {
+		start := strings.IndexByte(template, '{')
+		if start < 0 {
+			segments = append(segments, segment{text: template})
+			break
+		}
+		end := strings.IndexByte(template[start:], '}')
+		if end < 0 {
+			return nil, errUnclosedPlaceholder
+		}
+		if start > 0 {
+			segments = append(segments, segment{text: template[:start]})
+		}
+		segments = append(segments, segment{text: template[start+1 : start+end], field: true})
+		template = template[start+end+1:]
+		fields++
+	}
+	if fields == 0 {
+		return nil, errNoPlaceholder
+	}
+	return segments, nil
+}
@@ -0,0 +1,127 @@
+////////////////////////////////////////////////////////////////////////////////
+// Author:   Nikita Koryabkin
+// Email:    Nikita@Koryabk.in
+// Telegram: https://t.me/Apologiz
+////////////////////////////////////////////////////////////////////////////////
+
+package router
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/mylockerteam/alog/entry"
+	"github.com/spf13/afero"
+)
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		wantErr  bool
+	}{
+		{
+			template: "/var/log/app/tenants/{tenant}.log",
+			wantErr:  false,
+		},
+		{
+			template: "/var/log/app/tenants/{tenant.log",
+			wantErr:  true,
+		},
+		{
+			template: "/var/log/app/error.log",
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := New(tt.template); (err != nil) != tt.wantErr {
+				t.Errorf("New() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestStrategy_resolve(t *testing.T) {
+	s, _ := New("/var/log/{service}/{tenant}.log")
+	tests := []struct {
+		name   string
+		fields []entry.Field
+		want   string
+	}{
+		{
+			fields: []entry.Field{{Key: "tenant", Value: "acme"}, {Key: "service", Value: 42}},
+			want:   "/var/log/42/acme.log",
+		},
+		{
+			fields: []entry.Field{{Key: "tenant", Value: "acme"}},
+			want:   "/var/log/unknown/acme.log",
+		},
+		{
+			fields: []entry.Field{{Key: "tenant", Value: "../../etc/passwd"}, {Key: "service", Value: "%Y"}},
+			want:   "/var/log/_Y/.._.._etc_passwd.log",
+		},
+		{
+			fields: []entry.Field{{Key: "tenant", Value: ".."}, {Key: "service", Value: ""}},
+			want:   "/var/log/unknown/unknown.log",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := s.resolve(tt.fields); got != tt.want {
+				t.Errorf("resolve() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStrategy_WriteEntry(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	s, _ := New("/var/log/{tenant}.log", WithFs(fs), WithMaxOpen(2))
+	defer s.Close()
+	for _, tenant := range []string{"a", "b", "a", "c", "a"} {
+		e := &entry.Entry{Fields: []entry.Field{{Key: "tenant", Value: tenant}}}
+		if _, err := s.WriteEntry(e, []byte(tenant+"\n")); err != nil {
+			t.Fatalf("WriteEntry() error = %v", err)
+		}
+	}
+	if _, err := s.Write([]byte("plain\n")); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	for name, want := range map[string]string{"a": "a\na\na\n", "b": "b\n", "c": "c\n", "unknown": "plain\n"} {
+		if data, _ := afero.ReadFile(fs, "/var/log/"+name+".log"); string(data) != want {
+			t.Errorf("%s.log = %q, want %q", name, data, want)
+		}
+	}
+	if got, want := s.openPaths(), []string{"/var/log/unknown.log", "/var/log/a.log"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("open files = %v, want %v", got, want)
+	}
+}
+
+func TestStrategy_closeIdle(t *testing.T) {
+	defer func() { now = time.Now }()
+	current := time.Now()
+	now = func() time.Time { return current }
+	s, _ := New("/var/log/{tenant}.log", WithFs(afero.NewMemMapFs()), WithIdleTimeout(time.Minute))
+	defer s.Close()
+	for _, tenant := range []string{"a", "b"} {
+		_, _ = s.WriteEntry(&entry.Entry{Fields: []entry.Field{{Key: "tenant", Value: tenant}}}, []byte(tenant))
+		current = current.Add(time.Minute)
+	}
+	s.closeIdle(current.Add(-time.Minute))
+	if got, want := s.openPaths(), []string{"/var/log/b.log"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("open files = %v, want %v", got, want)
+	}
+}
+
+// openPaths returns the paths of the open files from the most recently used
+func (s *Strategy) openPaths() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var paths []string
+	for element := s.lru.Front(); element != nil; element = element.Next() {
+		paths = append(paths, element.Value.(*openFile).path)
+	}
+	return paths
+}
@@ -0,0 +1,335 @@
+////////////////////////////////////////////////////////////////////////////////
+// Author:   Nikita Koryabkin
+// Email:    Nikita@Koryabk.in
+// Telegram: https://t.me/Apologiz
+////////////////////////////////////////////////////////////////////////////////
+
+package smtp
+
+import (
+	"bytes"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/smtp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mylockerteam/alog/internal/diag"
+)
+
+const (
+	defaultTimeout   = 30 * time.Second
+	defaultQueueSize = 64
+	maxSubject       = 78
+)
+
+// Security of the connection
+type Security int
+
+const (
+	// None plain connection, credentials are only sent to localhost
+	None Security = iota
+	// StartTLS upgrades the connection with the STARTTLS command, fails if the server doesn't support it
+	StartTLS
+	// ImplicitTLS connects with TLS, usually to the port 465
+	ImplicitTLS
+)
+
+var errNoRecipients = errors.New("no recipients")
+var errStartTLSUnsupported = errors.New("server doesn't support STARTTLS")
+var errQueueFull = errors.New("smtp queue is full, the message is dropped")
+var errClosed = errors.New("smtp strategy is closed")
+
+// Strategy sends every message in an email through the SMTP server.
+// Messages are queued and sent in the background, so a slow server doesn't block the logger.
+// The connection is kept open between messages and redialed when it breaks
+type Strategy struct {
+	_         io.Writer
+	addr      string
+	host      string
+	from      string
+	to        []string
+	subject   string
+	security  Security
+	tlsConfig *tls.Config
+	auth      smtp.Auth
+	timeout   time.Duration
+	report    func(err error)
+	queue     chan []byte
+	done      chan struct{}
+
+	mu     sync.RWMutex
+	closed bool
+	conn   net.Conn
+	client *smtp.Client
+}
+
+// Option configures the SMTP strategy
+type Option func(s *Strategy)
+
+// New SMTP strategy for the server address host:port, it starts the sender, call Close on shutdown
+func New(addr, from string, to []string, options ...Option) (*Strategy, error) {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, err
+	}
+	if len(to) == 0 {
+		return nil, errNoRecipients
+	}
+	s := &Strategy{
+		addr:    addr,
+		host:    host,
+		from:    from,
+		to:      to,
+		timeout: defaultTimeout,
+		report: func(err error) {
+			diag.Println(err)
+		},
+		queue: make(chan []byte, defaultQueueSize),
+		done:  make(chan struct{}),
+	}
+	for _, option := range options {
+		option(s)
+	}
+	go s.sender()
+	return s, nil
+}
+
+// WithSecurity sets the security of the connection, the TLS config may be nil.
+// Without the server name in the config the host of the address is verified
+func WithSecurity(security Security, config *tls.Config) Option {
+	return func(s *Strategy) {
+		s.security = security
+		s.tlsConfig = config
+	}
+}
+
+// WithAuth authenticates with PLAIN, the credentials are sent only over TLS or to localhost
+func WithAuth(username, password string) Option {
+	return func(s *Strategy) {
+		s.auth = smtp.PlainAuth("", username, password, s.host)
+	}
+}
+
+// WithTimeout limits dialing and every email, 30 seconds by default
+func WithTimeout(timeout time.Duration) Option {
+	return func(s *Strategy) {
+		s.timeout = timeout
+	}
+}
+
+// WithSubject sets the subject, the first line of the message by default
+func WithSubject(subject string) Option {
+	return func(s *Strategy) {
+		s.subject = subject
+	}
+}
+
+// WithReport calls the function with every delivery error instead of logging it.
+// It is called from the sender goroutine
+func WithReport(report func(err error)) Option {
+	return func(s *Strategy) {
+		s.report = report
+	}
+}
+
+// WithQueueSize sets the number of messages waiting for delivery, 64 by default.
+// Write fails when the queue is full
+func WithQueueSize(size int) Option {
+	return func(s *Strategy) {
+		s.queue = make(chan []byte, size)
+	}
+}
+
+// Write queues the message, it fails if the queue is full or the strategy is closed
+func (s *Strategy) Write(p []byte) (n int, err error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.closed {
+		return 0, errClosed
+	}
+	select {
+	case s.queue <- s.message(p):
+		return len(p), nil
+	default:
+		return 0, errQueueFull
+	}
+}
+
+// Close sends the queued messages and quits the session with the server
+func (s *Strategy) Close() error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil
+	}
+	s.closed = true
+	close(s.queue)
+	s.mu.Unlock()
+	<-s.done
+	if s.client == nil {
+		return nil
+	}
+	err := s.conn.SetDeadline(time.Now().Add(s.timeout))
+	if err == nil {
+		err = s.client.Quit()
+	}
+	s.disconnect()
+	return err
+}
+
+func (s *Strategy) sender() {
+	defer close(s.done)
+	for msg := range s.queue {
+		if err := s.deliver(msg); err != nil {
+			s.report(err)
+		}
+	}
+}
+
+// deliver sends the message, a broken reused connection is redialed once.
+// The message is not sent again once the server got the data, so it is never duplicated
+func (s *Strategy) deliver(msg []byte) error {
+	reused := s.client != nil
+	data, err := s.send(msg)
+	if err != nil && reused && !data {
+		_, err = s.send(msg)
+	}
+	return err
+}
+
+// send delivers the message in one transaction, the connection is closed on error.
+// It reports whether the data of the message was started
+func (s *Strategy) send(msg []byte) (bool, error) {
+	if s.client == nil {
+		if err := s.connect(); err != nil {
+			return false, err
+		}
+	}
+	data, err := s.transaction(msg)
+	if err != nil {
+		s.disconnect()
+	}
+	return data, err
+}
+
+func (s *Strategy) transaction(msg []byte) (bool, error) {
+	if err := s.conn.SetDeadline(time.Now().Add(s.timeout)); err != nil {
+		return false, err
+	}
+	if err := s.client.Mail(s.from); err != nil {
+		return false, err
+	}
+	for _, to := range s.to {
+		if err := s.client.Rcpt(to); err != nil {
+			return false, err
+		}
+	}
+	w, err := s.client.Data()
+	if err != nil {
+		return false, err
+	}
+	if _, err := w.Write(msg); err != nil {
+		w.Close()
+		return true, err
+	}
+	return true, w.Close()
+}
+
+func (s *Strategy) connect() error {
+	dialer := &net.Dialer{Timeout: s.timeout}
+	conn, err := dialer.Dial("tcp", s.addr)
+	if err != nil {
+		return err
+	}
+	if s.security == ImplicitTLS {
+		conn = tls.Client(conn, s.config())
+	}
+	if err := conn.SetDeadline(time.Now().Add(s.timeout)); err != nil {
+		conn.Close()
+		return err
+	}
+	client, err := smtp.NewClient(conn, s.host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	if err := s.handshake(client); err != nil {
+		client.Close()
+		return err
+	}
+	s.conn, s.client = conn, client
+	return nil
+}
+
+// handshake greets the server, upgrades the connection and authenticates
+func (s *Strategy) handshake(client *smtp.Client) error {
+	if err := client.Hello("localhost"); err != nil {
+		return err
+	}
+	if s.security == StartTLS {
+		if ok, _ := client.Extension("STARTTLS"); !ok {
+			return errStartTLSUnsupported
+		}
+		if err := client.StartTLS(s.config()); err != nil {
+			return err
+		}
+	}
+	if s.auth != nil {
+		return client.Auth(s.auth)
+	}
+	return nil
+}
+
+func (s *Strategy) disconnect() {
+	if s.client != nil {
+		s.client.Close()
+	}
+	s.conn, s.client = nil, nil
+}
+
+func (s *Strategy) config() *tls.Config {
+	config := &tls.Config{}
+	if s.tlsConfig != nil {
+		config = s.tlsConfig.Clone()
+	}
+	if config.ServerName == "" {
+		config.ServerName = s.host
+	}
+	return config
+}
+
+// message returns the email with the headers and the quoted-printable text
+func (s *Strategy) message(p []byte) []byte {
+	subject := s.subject
+	if subject == "" {
+		subject = defaultSubject(string(p))
+	}
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "From: %s\r\n", s.from)
+	fmt.Fprintf(&b, "To: %s\r\n", strings.Join(s.to, ", "))
+	fmt.Fprintf(&b, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject))
+	fmt.Fprintf(&b, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	b.WriteString("Content-Transfer-Encoding: quoted-printable\r\n\r\n")
+	w := quotedprintable.NewWriter(&b)
+	_, _ = w.Write(p)
+	_ = w.Close()
+	return b.Bytes()
+}
+
+// defaultSubject returns the first line of the message shortened to fit the header line
+func defaultSubject(text string) string {
+	line := strings.TrimSpace(strings.SplitN(strings.TrimSpace(text), "\n", 2)[0])
+	if runes := []rune(line); len(runes) > maxSubject {
+		return string(runes[:maxSubject-3]) + "..."
+	}
+	return line
+}
@@ -0,0 +1,368 @@
+////////////////////////////////////////////////////////////////////////////////
+// Author:   Nikita Koryabkin
+// Email:    Nikita@Koryabk.in
+// Telegram: https://t.me/Apologiz
+////////////////////////////////////////////////////////////////////////////////
+
+package smtp
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"io"
+	"math/big"
+	"net"
+	"net/textproto"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+const testMsg = "Hello, ALog!"
+
+// fakeServer SMTP server keeping the received emails
+type fakeServer struct {
+	listener    net.Listener
+	tlsConfig   *tls.Config
+	startTLS    bool
+	rejectRcpt  bool
+	silent      bool
+	closeOnData bool
+	// dropData the connection is closed without the reply after the message with the number
+	dropData int
+
+	mu          sync.Mutex
+	connections int
+	auth        []string
+	messages    []string
+}
+
+func startFakeServer(t *testing.T, server *fakeServer, implicitTLS bool) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if implicitTLS {
+		listener = tls.NewListener(listener, server.tlsConfig)
+	}
+	server.listener = listener
+	t.Cleanup(func() { listener.Close() })
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			server.mu.Lock()
+			server.connections++
+			server.mu.Unlock()
+			go server.serve(conn)
+		}
+	}()
+	return listener.Addr().String()
+}
+
+func (f *fakeServer) serve(conn net.Conn) {
+	defer conn.Close()
+	if f.silent {
+		_, _ = io.Copy(io.Discard, conn)
+		return
+	}
+	tp := textproto.NewConn(conn)
+	_ = tp.PrintfLine("220 localhost ESMTP")
+	secure := false
+	for {
+		line, err := tp.ReadLine()
+		if err != nil {
+			return
+		}
+		switch command := strings.ToUpper(strings.Fields(line + " ")[0]); command {
+		case "EHLO":
+			if f.startTLS && !secure {
+				_ = tp.PrintfLine("250-localhost\r\n250-STARTTLS\r\n250 AUTH PLAIN")
+			} else {
+				_ = tp.PrintfLine("250-localhost\r\n250 AUTH PLAIN")
+			}
+		case "STARTTLS":
+			_ = tp.PrintfLine("220 Ready")
+			conn = tls.Server(conn, f.tlsConfig)
+			tp, secure = textproto.NewConn(conn), true
+		case "AUTH":
+			f.mu.Lock()
+			f.auth = append(f.auth, line)
+			f.mu.Unlock()
+			_ = tp.PrintfLine("235 Authenticated")
+		case "RCPT":
+			if f.rejectRcpt {
+				_ = tp.PrintfLine("550 No such user")
+			} else {
+				_ = tp.PrintfLine("250 OK")
+			}
+		case "DATA":
+			_ = tp.PrintfLine("354 Go ahead")
+			data, err := tp.ReadDotBytes()
+			if err != nil {
+				return
+			}
+			f.mu.Lock()
+			f.messages = append(f.messages, string(data))
+			drop := len(f.messages) == f.dropData
+			f.mu.Unlock()
+			if drop {
+				return
+			}
+			_ = tp.PrintfLine("250 OK")
+			if f.closeOnData {
+				return
+			}
+		case "QUIT":
+			_ = tp.PrintfLine("221 Bye")
+			return
+		case "MAIL", "RSET", "NOOP":
+			_ = tp.PrintfLine("250 OK")
+		default:
+			_ = tp.PrintfLine("502 Not implemented")
+		}
+	}
+}
+
+func (f *fakeServer) stats() (connections int, messages []string, auth []string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.connections, f.messages, f.auth
+}
+
+// testCertificate returns the server config with a self-signed certificate for 127.0.0.1 and the client pool
+func testCertificate(t *testing.T) (*tls.Config, *x509.CertPool) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, _ := x509.ParseCertificate(der)
+	pool := x509.NewCertPool()
+	pool.AddCert(cert)
+	return &tls.Config{Certificates: []tls.Certificate{{Certificate: [][]byte{der}, PrivateKey: key}}}, pool
+}
+
+func TestStrategy_Write(t *testing.T) {
+	serverConfig, pool := testCertificate(t)
+	tests := []struct {
+		name     string
+		server   *fakeServer
+		security Security
+	}{
+		{
+			server:   &fakeServer{},
+			security: None,
+		},
+		{
+			server:   &fakeServer{tlsConfig: serverConfig, startTLS: true},
+			security: StartTLS,
+		},
+		{
+			server:   &fakeServer{tlsConfig: serverConfig},
+			security: ImplicitTLS,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			addr := startFakeServer(t, tt.server, tt.security == ImplicitTLS)
+			s, err := New(addr, "no-reply@example.com", []string{"test@example.com"},
+				WithSecurity(tt.security, &tls.Config{RootCAs: pool}),
+				WithAuth("user", "password"),
+				WithTimeout(time.Second),
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for i := 0; i < 2; i++ {
+				if _, err := s.Write([]byte(testMsg + "\n")); err != nil {
+					t.Fatalf("Write() error = %v", err)
+				}
+			}
+			if err := s.Close(); err != nil {
+				t.Errorf("Close() error = %v", err)
+			}
+			connections, messages, auth := tt.server.stats()
+			if connections != 1 || len(messages) != 2 || len(auth) != 1 {
+				t.Fatalf("server got %d connections, %d messages, auth %v", connections, len(messages), auth)
+			}
+			if !strings.Contains(messages[0], "Subject: "+testMsg) || !strings.Contains(messages[0], "\n\n"+testMsg) {
+				t.Errorf("message = %q", messages[0])
+			}
+		})
+	}
+}
+
+func TestStrategy_Write_redial(t *testing.T) {
+	server := &fakeServer{closeOnData: true}
+	addr := startFakeServer(t, server, false)
+	s, _ := New(addr, "no-reply@example.com", []string{"test@example.com"})
+	for i := 0; i < 2; i++ {
+		if _, err := s.Write([]byte(testMsg)); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+	}
+	// the server has closed the connection, so QUIT fails
+	_ = s.Close()
+	if connections, messages, _ := server.stats(); connections != 2 || len(messages) != 2 {
+		t.Errorf("server got %d connections, %d messages", connections, len(messages))
+	}
+}
+
+func TestStrategy_Write_noDuplicates(t *testing.T) {
+	server := &fakeServer{dropData: 2}
+	addr := startFakeServer(t, server, false)
+	errs := make(chan error, 3)
+	s, _ := New(addr, "no-reply@example.com", []string{"test@example.com"},
+		WithTimeout(time.Second),
+		WithReport(func(err error) { errs <- err }),
+	)
+	for i := 0; i < 2; i++ {
+		if _, err := s.Write([]byte(testMsg)); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+	}
+	_ = s.Close()
+	close(errs)
+	if len(errs) != 1 {
+		t.Errorf("reported %d errors, want 1", len(errs))
+	}
+	if connections, messages, _ := server.stats(); connections != 1 || len(messages) != 2 {
+		t.Errorf("server got %d connections, %d messages", connections, len(messages))
+	}
+}
+
+func TestStrategy_Write_queue(t *testing.T) {
+	server := &fakeServer{silent: true}
+	addr := startFakeServer(t, server, false)
+	s, _ := New(addr, "no-reply@example.com", []string{"test@example.com"},
+		WithTimeout(100*time.Millisecond),
+		WithQueueSize(1),
+		WithReport(func(error) {}),
+	)
+	start := time.Now()
+	var full int
+	for i := 0; i < 3; i++ {
+		if _, err := s.Write([]byte(testMsg)); err == errQueueFull {
+			full++
+		}
+	}
+	if full == 0 || time.Since(start) > 50*time.Millisecond {
+		t.Errorf("Write() blocked or didn't report the full queue")
+	}
+	if err := s.Close(); err != nil {
+		t.Errorf("Close() error = %v", err)
+	}
+	if _, err := s.Write([]byte(testMsg)); err != errClosed {
+		t.Errorf("Write() after Close() error = %v, want %v", err, errClosed)
+	}
+}
+
+func TestStrategy_Write_errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		server   *fakeServer
+		security Security
+	}{
+		{
+			server: &fakeServer{rejectRcpt: true},
+		},
+		{
+			server:   &fakeServer{},
+			security: StartTLS,
+		},
+		{
+			server: &fakeServer{silent: true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			addr := startFakeServer(t, tt.server, false)
+			errs := make(chan error, 1)
+			s, _ := New(addr, "no-reply@example.com", []string{"test@example.com"},
+				WithSecurity(tt.security, nil),
+				WithTimeout(100*time.Millisecond),
+				WithReport(func(err error) { errs <- err }),
+			)
+			if _, err := s.Write([]byte(testMsg)); err != nil {
+				t.Fatalf("Write() error = %v", err)
+			}
+			_ = s.Close()
+			if len(errs) != 1 {
+				t.Errorf("delivery error was not reported")
+			}
+		})
+	}
+}
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name    string
+		addr    string
+		to      []string
+		wantErr bool
+	}{
+		{
+			addr:    "smtp.example.com:587",
+			to:      []string{"test@example.com"},
+			wantErr: false,
+		},
+		{
+			addr:    "smtp.example.com",
+			to:      []string{"test@example.com"},
+			wantErr: true,
+		},
+		{
+			addr:    "smtp.example.com:587",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := New(tt.addr, "no-reply@example.com", tt.to); (err != nil) != tt.wantErr {
+				t.Errorf("New() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_defaultSubject(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{
+			text: "\n[Error] refused\nstack",
+			want: "[Error] refused",
+		},
+		{
+			text: strings.Repeat("a", 100),
+			want: strings.Repeat("a", 75) + "...",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := defaultSubject(tt.text); got != tt.want {
+				t.Errorf("defaultSubject() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -8,7 +8,8 @@ package standart
 
 import (
 	"io"
-	"log"
+
+	"github.com/mylockerteam/alog/internal/diag"
 )
 
 // Strategy logging strategy in the console
@@ -16,12 +17,12 @@ type Strategy struct {
 	_ io.Writer
 }
 
-// Get console write strategy
+// Get console write strategy, it writes to stderr even when the standard logger is redirected into alog
 func Get() io.Writer {
 	return &Strategy{}
 }
 
 func (s *Strategy) Write(p []byte) (n int, err error) {
-	log.Println(string(p))
+	diag.Println(string(p))
 	return len(p), nil
 }
@@ -6,14 +6,26 @@
 
 package alog
 
-import "io"
+import (
+	"io"
 
-//Writer interface for loggers
+	"github.com/mylockerteam/alog/entry"
+)
+
+//go:generate mockgen -destination mocks/mock_Writer.go -package mocks github.com/mylockerteam/alog Writer
+
+//Writer interface for loggers.
+// The recording methods return Writer, so wrappers and mocks can be chained the same way as *Log
 type Writer interface {
-	Info(msg string) *Log
-	Infof(format string, p ...interface{}) *Log
-	Warning(msg string) *Log
-	Error(err error) *Log
-	ErrorDebug(err error) *Log
+	Debug(msg string) Writer
+	Debugf(format string, p ...interface{}) Writer
+	Info(msg string) Writer
+	Infof(format string, p ...interface{}) Writer
+	Warning(msg string) Writer
+	Error(err error) Writer
+	ErrorDebug(err error) Writer
 	GetLoggerInterfaceByType(loggerType uint) io.Writer
+	RedirectStdLog(loggerType uint) (func(), error)
+	Enabled(loggerType uint) bool
+	Dispatch(e *entry.Entry) Writer
 }
@@ -10,11 +10,51 @@ import (
 	"fmt"
 	"io"
 	"reflect"
+	"strings"
 	"testing"
 
+	"github.com/mylockerteam/alog/entry"
 	"github.com/mylockerteam/alog/strategy/standart"
 )
 
+func TestLog_Debug(t *testing.T) {
+	config := &Config{
+		Loggers: Map{
+			Debug: loggerProvider(),
+		},
+	}
+	tests := []struct {
+		name  string
+		write func(w Writer) Writer
+		want  string
+	}{
+		{
+			write: func(w Writer) Writer {
+				return w.Debug(testMsg)
+			},
+			want: testMsg,
+		},
+		{
+			write: func(w Writer) Writer {
+				return w.Debugf("%s %d", testMsg, 1)
+			},
+			want: testMsg + " 1",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := &Log{config: config}
+			if got := tt.write(w); got != w {
+				t.Errorf("Log.Debug() = %v, want %v", got, w)
+			}
+			got := <-config.Loggers[Debug].Channel
+			if !strings.HasPrefix(got, "[Debug] ") || !strings.HasSuffix(got, ";"+tt.want+"\n") {
+				t.Errorf("Log.Debug() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 type argsLogInfo struct {
 	msg string
 }
@@ -392,3 +432,88 @@ func TestLog_ErrorDebug(t *testing.T) {
 		})
 	}
 }
+
+func TestLog_Enabled(t *testing.T) {
+	tests := []struct {
+		name       string
+		loggerType uint
+		want       bool
+	}{
+		{
+			loggerType: Info,
+			want:       true,
+		},
+		{
+			loggerType: Err,
+			want:       false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := (&Log{config: configProvider()}).Enabled(tt.loggerType); got != tt.want {
+				t.Errorf("Log.Enabled() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLog_Dispatch(t *testing.T) {
+	tests := []struct {
+		name  string
+		entry *entry.Entry
+		want  string
+	}{
+		{
+			entry: &entry.Entry{
+				Level:   Info,
+				Message: testMsg,
+				Fields:  []entry.Field{{Key: "a", Value: 1}},
+			},
+			want: fmt.Sprintf("[%s] ", Name(Info)),
+		},
+		{
+			entry: &entry.Entry{
+				Level:   Err,
+				Message: testMsg,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := configProvider()
+			(&Log{config: config}).Dispatch(tt.entry)
+			if tt.want == "" {
+				return
+			}
+			got := <-config.Loggers[tt.entry.Level].Channel
+			if !strings.HasPrefix(got, tt.want) || !strings.HasSuffix(got, ";"+testMsg+" a=1\n") {
+				t.Errorf("Log.Dispatch() = %v, want %v", got, tt.want)
+			}
+			if tt.entry.Time.IsZero() || tt.entry.LevelName != Name(tt.entry.Level) {
+				t.Errorf("Log.Dispatch() time and level name were not set")
+			}
+		})
+	}
+}
+
+// prefixWriter decorator which adds the prefix to informational messages
+type prefixWriter struct {
+	Writer
+	prefix string
+}
+
+func (w *prefixWriter) Info(msg string) Writer {
+	w.Writer.Info(w.prefix + msg)
+	return w
+}
+
+func TestWriter_decorator(t *testing.T) {
+	config := configProvider()
+	var w Writer = &prefixWriter{Writer: &Log{config: config}, prefix: "prefix "}
+	if got := w.Info(testMsg); got != w {
+		t.Errorf("Writer.Info() = %v, want %v", got, w)
+	}
+	if got := <-config.Loggers[Info].Channel; !strings.HasSuffix(got, ";prefix "+testMsg+"\n") {
+		t.Errorf("Writer.Info() = %v, want prefixed message", got)
+	}
+}